@@ -1,7 +1,16 @@
 package local
 
+import (
+	"strings"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+)
+
 type DockercliLocalHandlerBase struct {
 	config DockercliLocalConfig
+	// endpoint is the name of the yml endpoint that the handler targets, or
+	// empty for the default daemon
+	endpoint string
 }
 
 func New_DockercliLocalHandlerBase(config DockercliLocalConfig) *DockercliLocalHandlerBase {
@@ -17,3 +26,60 @@ func (base *DockercliLocalHandlerBase) DockercliLocalConfig() DockercliLocalConf
 func (base *DockercliLocalHandlerBase) SetDockercliLocalConfig(config DockercliLocalConfig) {
 	base.config = config
 }
+
+// Name of the endpoint that the handler targets
+func (base *DockercliLocalHandlerBase) Endpoint() string {
+	return base.endpoint
+}
+
+// Target a named endpoint, which scopes the handler and operation ids
+func (base *DockercliLocalHandlerBase) SetEndpoint(endpoint string) {
+	base.endpoint = endpoint
+}
+
+// Scope an id such as dockercli.orchestrate to the endpoint, as dockercli.{endpoint}.orchestrate
+func (base *DockercliLocalHandlerBase) endpointId(id string) string {
+	if base.endpoint == "" {
+		return id
+	}
+	return "dockercli." + base.endpoint + strings.TrimPrefix(id, "dockercli")
+}
+
+// Scope the ids of operations to the endpoint
+func (base *DockercliLocalHandlerBase) endpointOperations(ops api_operation.Operations) api_operation.Operations {
+	if base.endpoint == "" {
+		return ops
+	}
+
+	scoped := api_operation.New_SimpleOperations()
+	for _, id := range ops.Order() {
+		if op, found := ops.Get(id); found {
+			scoped.Add(api_operation.Operation(&DockercliEndpointOperation{
+				Operation: op,
+				base:      base,
+			}))
+		}
+	}
+	return scoped.Operations()
+}
+
+/**
+ * Endpoint operation
+ */
+
+// An operation that targets a named endpoint, so that the same operation can
+// be run against several daemons
+type DockercliEndpointOperation struct {
+	api_operation.Operation
+	base *DockercliLocalHandlerBase
+}
+
+// Id the operation, scoped to the endpoint
+func (endpointOp *DockercliEndpointOperation) Id() string {
+	return endpointOp.base.endpointId(endpointOp.Operation.Id())
+}
+
+// Label the operation, naming the endpoint
+func (endpointOp *DockercliEndpointOperation) Label() string {
+	return endpointOp.Operation.Label() + " (" + endpointOp.base.Endpoint() + ")"
+}
@@ -35,12 +35,8 @@ type LocalBuilder struct {
 	DockercliStackHandlerBase_common *handler_dockercli_stack.DockercliStackHandlerBase
 }
 
-// Constructor for LocalBuilder
+// Constructor for LocalBuilder, a nil dockercliConfig is built from the project config on activation
 func New_LocalBuilder(settings handler_local.LocalAPISettings, dockercliConfig DockercliLocalConfig) *LocalBuilder {
-	if dockercliConfig == nil {
-		dockercliConfig = &DockercliLocalConfigDefault{settings: settings}
-	}
-
 	return &LocalBuilder{
 		LocalBuilder:    *handler_local.New_LocalBuilder(settings),
 		settings:        settings,
@@ -76,16 +72,41 @@ func (builder *LocalBuilder) Activate(implementations api_builder.Implementation
 	 * Here you could override that bases depending on the settings
 	 */
 
+	builder.build_Implementations(implementations, "", localBase, dockerCLIBase, stackBase)
+
+	// Each named endpoint gets its own handlers, with endpoint scoped operation ids
+	if endpointsConfig, ok := dockercliConfig.(DockercliLocalEndpointsConfig); ok {
+		for _, endpoint := range endpointsConfig.Endpoints() {
+			endpointConfig, err := endpointsConfig.Endpoint(endpoint)
+			if err != nil {
+				log.WithError(err).WithFields(log.Fields{"endpoint": endpoint}).Error("Could not configure dockercli endpoint")
+				continue
+			}
+
+			endpointCLIBase := builder.base_EndpointDockercliHandlerBase(endpointConfig)
+			endpointStackBase := handler_dockercli_stack.New_DockercliStackHandlerBase(handler_dockercli_stack.DockercliStackConfig(endpointConfig))
+
+			builder.build_Implementations(implementations, endpoint, localBase, endpointCLIBase, endpointStackBase)
+		}
+	}
+
+	return api_result.MakeSuccessfulResult()
+}
+
+// Build handlers for the implementations, targeting an endpoint if one is named
+func (builder *LocalBuilder) build_Implementations(implementations api_builder.Implementations, endpoint string, localBase *handler_local.LocalHandler_Base, dockerCLIBase *handler_dockercli.DockercliHandlerBase, stackBase *handler_dockercli_stack.DockercliStackHandlerBase) {
 	for _, implementation := range implementations.Order() {
 		switch implementation {
 		case "orchestrate":
-			builder.build_Orchestrate(localBase, dockerCLIBase, stackBase)
+			builder.build_Orchestrate(localBase, dockerCLIBase, stackBase, endpoint)
+		case "monitor":
+			builder.build_Monitor(localBase, dockerCLIBase, stackBase, endpoint)
+		case "command":
+			builder.build_Command(localBase, dockerCLIBase, stackBase, endpoint)
 		default:
 			log.WithFields(log.Fields{"implementation": implementation}).Warn("Local builder implementation not available")
 		}
 	}
-
-	return api_result.MakeSuccessfulResult()
 }
 
 /**
@@ -110,15 +131,21 @@ func (builder *LocalBuilder) SettingWrapper() api_setting.SettingWrapper {
 	return builder.Setting
 }
 
-// Build a cli config for this builder (currently just builds a single yml based configure, regardless of any settings.)
+// Build a cli config for this builder, from the dockercli yml if the project has one, or else the default config
 func (builder *LocalBuilder) DockercliConfig(settingsProvider api_builder.SettingsProvider) DockercliLocalConfig {
 	if builder.dockercliConfig == nil {
 		settings := builder.LocalAPISettings()
 		settingWrapper := builder.SettingWrapper()
-		// configWrapper := builder.ConfigWrapper()
-
-		builder.dockercliConfig = New_DockercliLocalConfigDefault(settings, settingWrapper)
-		// builder.dockercliConfig := New_DockercliLocalConfigConfigWrapperYml(settings, configWrapper)
+		configWrapper := builder.ConfigWrapper()
+
+		if _, err := configWrapper.Get(CONFIG_KEY_DOCKERCLI_LOCAL); err == nil {
+			configYml := New_DockercliLocalConfigConfigWrapperYml(settings, configWrapper)
+			configYml.DockercliLocalConfigDefault = *New_DockercliLocalConfigDefault(settings, settingWrapper)
+			builder.dockercliConfig = configYml.DockercliLocalConfig()
+		} else {
+			log.WithError(err).Debug("DockerCLI:localBuilder: No dockercli yml config, using the default config")
+			builder.dockercliConfig = New_DockercliLocalConfigDefault(settings, settingWrapper)
+		}
 	}
 	return builder.dockercliConfig
 }
@@ -137,6 +164,14 @@ func (builder *LocalBuilder) base_DockercliHandlerBase(dockercliConfig Dockercli
 	return builder.DockercliHandlerBase_common
 }
 
+// Endpoints each connect to their own daemon, so their bases are not shared
+func (builder *LocalBuilder) base_EndpointDockercliHandlerBase(endpointConfig DockercliLocalConfig) *handler_dockercli.DockercliHandlerBase {
+	in, out, err := endpointConfig.IO()
+	opts := endpointConfig.ClientOptions()
+
+	return handler_dockercli.New_DockercliHandlerBase(in, out, err, opts)
+}
+
 func (builder *LocalBuilder) base_DockercliStackHandlerBase(dockercliConfig DockercliLocalConfig) *handler_dockercli_stack.DockercliStackHandlerBase {
 	if builder.DockercliStackHandlerBase_common == nil {
 		dockercliStackConfig := handler_dockercli_stack.DockercliStackConfig(builder.dockercliConfig)
@@ -150,8 +185,9 @@ func (builder *LocalBuilder) base_DockercliStackHandlerBase(dockercliConfig Dock
  */
 
 // Build and add a handler for orchestration
-func (builder *LocalBuilder) build_Orchestrate(localBase *handler_local.LocalHandler_Base, dockerCLIBase *handler_dockercli.DockercliHandlerBase, stackBase *handler_dockercli_stack.DockercliStackHandlerBase) api_result.Result {
+func (builder *LocalBuilder) build_Orchestrate(localBase *handler_local.LocalHandler_Base, dockerCLIBase *handler_dockercli.DockercliHandlerBase, stackBase *handler_dockercli_stack.DockercliStackHandlerBase, endpoint string) api_result.Result {
 	local_orchestration := New_DockercliOrchestrateHandler(localBase, dockerCLIBase, stackBase)
+	local_orchestration.SetEndpoint(endpoint)
 
 	res := local_orchestration.Validate()
 	<-res.Finished()
@@ -161,7 +197,41 @@ func (builder *LocalBuilder) build_Orchestrate(localBase *handler_local.LocalHan
 		// Get an orchestrate wrapper for other handlers
 		//builder.Orchestrate = local_orchestration.OrchestrateWrapper()
 
-		log.Debug("DockerCLI:localBuilder: Built Orchestrate handler")
+		log.WithFields(log.Fields{"endpoint": endpoint}).Debug("DockerCLI:localBuilder: Built Orchestrate handler")
+	}
+
+	return res
+}
+
+// Build and add a handler for monitoring
+func (builder *LocalBuilder) build_Monitor(localBase *handler_local.LocalHandler_Base, dockerCLIBase *handler_dockercli.DockercliHandlerBase, stackBase *handler_dockercli_stack.DockercliStackHandlerBase, endpoint string) api_result.Result {
+	local_monitor := New_DockercliMonitorHandler(localBase, dockerCLIBase, stackBase)
+	local_monitor.SetEndpoint(endpoint)
+
+	res := local_monitor.Validate()
+	<-res.Finished()
+
+	if res.Success() {
+		builder.AddHandler(api_handler.Handler(local_monitor))
+
+		log.WithFields(log.Fields{"endpoint": endpoint}).Debug("DockerCLI:localBuilder: Built Monitor handler")
+	}
+
+	return res
+}
+
+// Build and add a handler for commands
+func (builder *LocalBuilder) build_Command(localBase *handler_local.LocalHandler_Base, dockerCLIBase *handler_dockercli.DockercliHandlerBase, stackBase *handler_dockercli_stack.DockercliStackHandlerBase, endpoint string) api_result.Result {
+	local_command := New_DockercliCommandHandler(localBase, dockerCLIBase, stackBase)
+	local_command.SetEndpoint(endpoint)
+
+	res := local_command.Validate()
+	<-res.Finished()
+
+	if res.Success() {
+		builder.AddHandler(api_handler.Handler(local_command))
+
+		log.WithFields(log.Fields{"endpoint": endpoint}).Debug("DockerCLI:localBuilder: Built Command handler")
 	}
 
 	return res
@@ -0,0 +1,62 @@
+package local
+
+import (
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_result "github.com/wunderkraut/radi-api/result"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack "github.com/wunderkraut/radi-handler-dockercli/stack"
+	handler_local "github.com/wunderkraut/radi-handlers/local"
+)
+
+/**
+ * Handler for local commands through dockercli, such as executing tools in
+ * the containers of the stack services
+ */
+
+// Local Handler for commands using docker cli
+type DockercliCommandHandler struct {
+	handler_local.LocalHandler_Base
+	DockercliLocalHandlerBase
+	handler_dockercli.DockercliHandlerBase
+	handler_dockercli_stack.DockercliStackHandlerBase
+}
+
+// Constructor for DockercliCommandHandler
+func New_DockercliCommandHandler(localBase *handler_local.LocalHandler_Base, dockerCLIBase *handler_dockercli.DockercliHandlerBase, stackBase *handler_dockercli_stack.DockercliStackHandlerBase) *DockercliCommandHandler {
+	return &DockercliCommandHandler{
+		LocalHandler_Base:         *localBase,
+		DockercliHandlerBase:      *dockerCLIBase,
+		DockercliStackHandlerBase: *stackBase,
+	}
+}
+
+// Validate the Base Handler
+func (base *DockercliCommandHandler) Id() string {
+	return base.endpointId("dockercli.command")
+}
+
+// Validate the Base Handler
+func (base *DockercliCommandHandler) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Validate the Base Handler
+func (base *DockercliCommandHandler) Operations() api_operation.Operations {
+	ops := api_operation.New_SimpleOperations()
+
+	// use a single base operation
+	baseCliOp := base.DockercliOperationBase()
+	baseStackOp := base.DockercliStackOperationBase()
+
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackExecOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackRunOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+
+	return base.endpointOperations(ops.Operations())
+}
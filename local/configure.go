@@ -28,6 +28,20 @@ type DockercliLocalConfig interface {
 	 */
 	DeployOptions() *handler_dockercli_stack_imported.DeployOptions
 	RemoveOptions() *handler_dockercli_stack_imported.RemoveOptions
+	RollbackOptions() *handler_dockercli_stack_imported.RollbackOptions
+	HistoryOptions() *handler_dockercli_stack_imported.HistoryOptions
+	ExportOptions() *handler_dockercli_stack_imported.ExportOptions
+	VolumesOptions() *handler_dockercli_stack_imported.VolumesOptions
+	LogsOptions() *handler_dockercli_stack_imported.LogsOptions
+	ExecOptions() *handler_dockercli_stack_imported.ExecOptions
+	RunOptions() *handler_dockercli_stack_imported.RunOptions
+	ScaleOptions() *handler_dockercli_stack_imported.ScaleOptions
+	StartStopOptions() *handler_dockercli_stack_imported.StartStopOptions
+	BuildOptions() *handler_dockercli_stack_imported.BuildOptions
+	ImagesOptions() *handler_dockercli_stack_imported.ImagesOptions
+	WatchOptions() *handler_dockercli_stack_imported.WatchOptions
+	EventsOptions() *handler_dockercli_stack_imported.EventsOptions
+	StatusOptions() *handler_dockercli_stack_imported.StatusOptions
 }
 
 /**
@@ -53,6 +67,62 @@ func (nullsettings *DockercliLocalConfigNull) RemoveOptions() *handler_dockercli
 	return handler_dockercli_stack_imported.New_RemoveOptions("")
 }
 
+func (nullsettings *DockercliLocalConfigNull) RollbackOptions() *handler_dockercli_stack_imported.RollbackOptions {
+	return handler_dockercli_stack_imported.New_RollbackOptions("")
+}
+
+func (nullsettings *DockercliLocalConfigNull) HistoryOptions() *handler_dockercli_stack_imported.HistoryOptions {
+	return handler_dockercli_stack_imported.New_HistoryOptions("", "", false)
+}
+
+func (nullsettings *DockercliLocalConfigNull) ExportOptions() *handler_dockercli_stack_imported.ExportOptions {
+	return handler_dockercli_stack_imported.New_ExportOptions("", "")
+}
+
+func (nullsettings *DockercliLocalConfigNull) VolumesOptions() *handler_dockercli_stack_imported.VolumesOptions {
+	return handler_dockercli_stack_imported.New_VolumesOptions("")
+}
+
+func (nullsettings *DockercliLocalConfigNull) LogsOptions() *handler_dockercli_stack_imported.LogsOptions {
+	return handler_dockercli_stack_imported.New_LogsOptions("")
+}
+
+func (nullsettings *DockercliLocalConfigNull) ExecOptions() *handler_dockercli_stack_imported.ExecOptions {
+	return handler_dockercli_stack_imported.New_ExecOptions("", false, false)
+}
+
+func (nullsettings *DockercliLocalConfigNull) RunOptions() *handler_dockercli_stack_imported.RunOptions {
+	return handler_dockercli_stack_imported.New_RunOptions("")
+}
+
+func (nullsettings *DockercliLocalConfigNull) ScaleOptions() *handler_dockercli_stack_imported.ScaleOptions {
+	return handler_dockercli_stack_imported.New_ScaleOptions("")
+}
+
+func (nullsettings *DockercliLocalConfigNull) StartStopOptions() *handler_dockercli_stack_imported.StartStopOptions {
+	return handler_dockercli_stack_imported.New_StartStopOptions("")
+}
+
+func (nullsettings *DockercliLocalConfigNull) BuildOptions() *handler_dockercli_stack_imported.BuildOptions {
+	return handler_dockercli_stack_imported.New_BuildOptions("", "")
+}
+
+func (nullsettings *DockercliLocalConfigNull) ImagesOptions() *handler_dockercli_stack_imported.ImagesOptions {
+	return handler_dockercli_stack_imported.New_ImagesOptions("", "")
+}
+
+func (nullsettings *DockercliLocalConfigNull) WatchOptions() *handler_dockercli_stack_imported.WatchOptions {
+	return handler_dockercli_stack_imported.New_WatchOptions(*handler_dockercli_stack_imported.New_DeployOptions("", "", "", false))
+}
+
+func (nullsettings *DockercliLocalConfigNull) EventsOptions() *handler_dockercli_stack_imported.EventsOptions {
+	return handler_dockercli_stack_imported.New_EventsOptions("")
+}
+
+func (nullsettings *DockercliLocalConfigNull) StatusOptions() *handler_dockercli_stack_imported.StatusOptions {
+	return handler_dockercli_stack_imported.New_StatusOptions("")
+}
+
 func (nullsettings *DockercliLocalConfigNull) IO() (io.ReadCloser, io.Writer, io.Writer) {
 	return os.Stdin, os.Stdout, os.Stderr
 }
@@ -83,33 +153,157 @@ func (defaultsettings *DockercliLocalConfigDefault) ClientOptions() *docker_cli_
 	return docker_cli_flags.NewClientOptions()
 }
 
-func (defaultsettings *DockercliLocalConfigDefault) DeployOptions() *handler_dockercli_stack_imported.DeployOptions {
+// Stack namespace used for all stack options
+func (defaultsettings *DockercliLocalConfigDefault) projectName() string {
 	// projectName, err := defaultsettings.settingWrapper.Get("Project")
 	// if err != nil {
 	// 	projectName = "default"
 	// }
-	projectName := "default"
+	return "default"
+}
+
+// Path used to record the stack deploy history
+func (defaultsettings *DockercliLocalConfigDefault) historyPath() string {
+	return path.Join(defaultsettings.settings.ProjectRootPath, ".radi", "dockercli", "history")
+}
+
+func (defaultsettings *DockercliLocalConfigDefault) DeployOptions() *handler_dockercli_stack_imported.DeployOptions {
+	projectName := defaultsettings.projectName()
 
-	return handler_dockercli_stack_imported.New_DeployOptions(
+	deployOptions := handler_dockercli_stack_imported.New_DeployOptions(
 		"", // bundlefile,
 		path.Join(defaultsettings.settings.ProjectRootPath, "docker-compose.yml"), // composefile,
 		projectName, // namespace,
 		false,       // sendRegistryAuth,
 	)
+	deployOptions.SetHistoryPath(defaultsettings.historyPath())
+
+	return deployOptions
 }
 
 func (defaultsettings *DockercliLocalConfigDefault) RemoveOptions() *handler_dockercli_stack_imported.RemoveOptions {
-	// projectName, err := defaultsettings.settingWrapper.Get("Project")
-	// if err != nil {
-	// 	projectName = "default"
-	// }
-	projectName := "default"
+	projectName := defaultsettings.projectName()
 
 	return handler_dockercli_stack_imported.New_RemoveOptions(
 		projectName, // namespace,
 	)
 }
 
+func (defaultsettings *DockercliLocalConfigDefault) RollbackOptions() *handler_dockercli_stack_imported.RollbackOptions {
+	projectName := defaultsettings.projectName()
+
+	return handler_dockercli_stack_imported.New_RollbackOptions(
+		projectName, // namespace,
+	)
+}
+
+func (defaultsettings *DockercliLocalConfigDefault) HistoryOptions() *handler_dockercli_stack_imported.HistoryOptions {
+	projectName := defaultsettings.projectName()
+
+	return handler_dockercli_stack_imported.New_HistoryOptions(
+		defaultsettings.historyPath(), // historyPath,
+		projectName,                   // namespace,
+		false,                         // sendRegistryAuth,
+	)
+}
+
+func (defaultsettings *DockercliLocalConfigDefault) ExportOptions() *handler_dockercli_stack_imported.ExportOptions {
+	projectName := defaultsettings.projectName()
+
+	return handler_dockercli_stack_imported.New_ExportOptions(
+		projectName, // namespace,
+		"",          // composefile, (empty writes to the cli output)
+	)
+}
+
+func (defaultsettings *DockercliLocalConfigDefault) VolumesOptions() *handler_dockercli_stack_imported.VolumesOptions {
+	projectName := defaultsettings.projectName()
+
+	return handler_dockercli_stack_imported.New_VolumesOptions(
+		projectName, // namespace,
+	)
+}
+
+func (defaultsettings *DockercliLocalConfigDefault) LogsOptions() *handler_dockercli_stack_imported.LogsOptions {
+	projectName := defaultsettings.projectName()
+
+	return handler_dockercli_stack_imported.New_LogsOptions(
+		projectName, // namespace,
+	)
+}
+
+func (defaultsettings *DockercliLocalConfigDefault) ExecOptions() *handler_dockercli_stack_imported.ExecOptions {
+	projectName := defaultsettings.projectName()
+
+	return handler_dockercli_stack_imported.New_ExecOptions(
+		projectName, // namespace,
+		true,        // tty,
+		true,        // interactive,
+	)
+}
+
+func (defaultsettings *DockercliLocalConfigDefault) RunOptions() *handler_dockercli_stack_imported.RunOptions {
+	projectName := defaultsettings.projectName()
+
+	return handler_dockercli_stack_imported.New_RunOptions(
+		projectName, // namespace,
+	)
+}
+
+func (defaultsettings *DockercliLocalConfigDefault) ScaleOptions() *handler_dockercli_stack_imported.ScaleOptions {
+	projectName := defaultsettings.projectName()
+
+	return handler_dockercli_stack_imported.New_ScaleOptions(
+		projectName, // namespace,
+	)
+}
+
+func (defaultsettings *DockercliLocalConfigDefault) StartStopOptions() *handler_dockercli_stack_imported.StartStopOptions {
+	projectName := defaultsettings.projectName()
+
+	return handler_dockercli_stack_imported.New_StartStopOptions(
+		projectName, // namespace,
+	)
+}
+
+func (defaultsettings *DockercliLocalConfigDefault) BuildOptions() *handler_dockercli_stack_imported.BuildOptions {
+	projectName := defaultsettings.projectName()
+
+	return handler_dockercli_stack_imported.New_BuildOptions(
+		path.Join(defaultsettings.settings.ProjectRootPath, "docker-compose.yml"), // composefile,
+		projectName, // namespace,
+	)
+}
+
+func (defaultsettings *DockercliLocalConfigDefault) ImagesOptions() *handler_dockercli_stack_imported.ImagesOptions {
+	projectName := defaultsettings.projectName()
+
+	return handler_dockercli_stack_imported.New_ImagesOptions(
+		path.Join(defaultsettings.settings.ProjectRootPath, "docker-compose.yml"), // composefile,
+		projectName, // namespace,
+	)
+}
+
+func (defaultsettings *DockercliLocalConfigDefault) WatchOptions() *handler_dockercli_stack_imported.WatchOptions {
+	return handler_dockercli_stack_imported.New_WatchOptions(*defaultsettings.DeployOptions())
+}
+
+func (defaultsettings *DockercliLocalConfigDefault) EventsOptions() *handler_dockercli_stack_imported.EventsOptions {
+	projectName := defaultsettings.projectName()
+
+	return handler_dockercli_stack_imported.New_EventsOptions(
+		projectName, // namespace,
+	)
+}
+
+func (defaultsettings *DockercliLocalConfigDefault) StatusOptions() *handler_dockercli_stack_imported.StatusOptions {
+	projectName := defaultsettings.projectName()
+
+	return handler_dockercli_stack_imported.New_StatusOptions(
+		projectName, // namespace,
+	)
+}
+
 func (defaultsettings *DockercliLocalConfigDefault) IO() (io.ReadCloser, io.Writer, io.Writer) {
 	return os.Stdin, os.Stdout, os.Stderr
 }
@@ -0,0 +1,166 @@
+package local
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+
+	docker_cli_flags "github.com/docker/docker/cli/flags"
+
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack"
+)
+
+/**
+ * Endpoints
+ *
+ * The yml can name several docker daemons or swarms, such as staging and
+ * production.  Each endpoint gets a configuration of its own, which connects
+ * to the endpoint host.  The endpoint Deploy options are merged over the top
+ * level Deploy options key by key, so an endpoint only sets what differs.
+ */
+
+// A configuration that provides named endpoints, each with its own configuration
+type DockercliLocalEndpointsConfig interface {
+	// Names of the configured endpoints
+	Endpoints() []string
+	// Configuration for a named endpoint
+	Endpoint(name string) (DockercliLocalConfig, error)
+}
+
+// Names of the endpoints in the yml, sorted
+func (configYml *DockercliLocalConfigConfigWrapperYml) Endpoints() []string {
+	configYml.safe()
+
+	names := []string{}
+	for name := range configYml.config.Endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Configuration for a named endpoint from the yml
+func (configYml *DockercliLocalConfigConfigWrapperYml) Endpoint(name string) (DockercliLocalConfig, error) {
+	configYml.safe()
+
+	endpoint, exists := configYml.config.Endpoints[name]
+	if !exists {
+		return nil, fmt.Errorf("No dockercli endpoint named %q", name)
+	}
+	if endpoint.Host == "" {
+		return nil, fmt.Errorf("The dockercli endpoint %q has no Host", name)
+	}
+
+	endpointYml := *configYml
+	endpointYml.endpoint = &endpoint
+	endpointYml.endpointName = name
+
+	if endpoint.Mode != "" {
+		endpointYml.config.Mode = endpoint.Mode
+	}
+	// check the client options now, so that a broken endpoint is not built
+	if _, err := endpointYml.endpointClientOptions(); err != nil {
+		return nil, fmt.Errorf("The dockercli endpoint %q has invalid client options: %s", name, err)
+	}
+
+	if endpoint.Deploy != nil {
+		deploy, err := configYml.endpointDeployOptions(endpoint.Deploy)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid Deploy options for the dockercli endpoint %q: %s", name, err)
+		}
+		endpointYml.config.DeployOptions = deploy
+	}
+
+	return DockercliLocalConfig(&endpointYml), nil
+}
+
+// endpointDeployOptions merges endpoint deploy options over the top level
+// deploy options.  Only the keys given for the endpoint are replaced, so that
+// explicit false and zero values also override the top level.
+func (configYml *DockercliLocalConfigConfigWrapperYml) endpointDeployOptions(endpointDeploy map[string]interface{}) (dockercliLocalConfigureYML_DeployOptions, error) {
+	deploy := configYml.config.DeployOptions
+
+	// the endpoint deploys the same stack, unless it names another one
+	_, composefile := endpointDeploy["Composefile"]
+	_, bundlefile := endpointDeploy["Bundlefile"]
+	if composefile || bundlefile {
+		deploy.Composefile = ""
+		deploy.Bundlefile = ""
+	}
+
+	// copy the pointer and map values, so that the endpoint does not change
+	// the top level
+	if deploy.UpdateRetries != nil {
+		updateRetries := *deploy.UpdateRetries
+		deploy.UpdateRetries = &updateRetries
+	}
+	credentials := deploy.RegistryCredentials
+	deploy.RegistryCredentials = nil
+	if len(credentials) > 0 {
+		deploy.RegistryCredentials = map[string]handler_dockercli_stack_imported.RegistryCredential{}
+		for registry, credential := range credentials {
+			deploy.RegistryCredentials[registry] = credential
+		}
+	}
+
+	source, err := yaml.Marshal(endpointDeploy)
+	if err != nil {
+		return deploy, err
+	}
+	err = yaml.Unmarshal(source, &deploy)
+	return deploy, err
+}
+
+// Client options that connect to the endpoint host, with TLS if configured
+func (configYml *DockercliLocalConfigConfigWrapperYml) endpointClientOptions() (*docker_cli_flags.ClientOptions, error) {
+	endpoint := configYml.endpoint
+	opts := docker_cli_flags.NewClientOptions()
+
+	opts.Common.Hosts = []string{endpoint.Host}
+	opts.Common.TLS = endpoint.TLS || endpoint.TLSVerify
+	opts.Common.TLSVerify = endpoint.TLSVerify
+	if opts.Common.TLS {
+		// the tls options are filled by field name, as the tlsconfig package
+		// is vendored inside the docker package
+		tlsOptions := map[string]interface{}{
+			"CAFile":             configYml.projectPath(endpoint.TLSCACert),
+			"CertFile":           configYml.projectPath(endpoint.TLSCert),
+			"KeyFile":            configYml.projectPath(endpoint.TLSKey),
+			"InsecureSkipVerify": !endpoint.TLSVerify,
+		}
+		if err := assignFields(&opts.Common.TLSOptions, tlsOptions); err != nil {
+			return opts, fmt.Errorf("Could not set the TLS options: %s", err)
+		}
+	}
+
+	return opts, nil
+}
+
+// assignFields sets struct fields by name through json, and checks that every
+// value arrived, as json silently skips names that the struct does not have
+func assignFields(target interface{}, values map[string]interface{}) error {
+	source, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(source, target); err != nil {
+		return err
+	}
+
+	assigned, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(assigned, &result); err != nil {
+		return err
+	}
+	for name, value := range values {
+		if result[name] != value {
+			return fmt.Errorf("the field %s was not set", name)
+		}
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package local
 import (
 	"errors"
 	"io"
+	"path"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 	"gopkg.in/yaml.v2"
@@ -25,6 +27,11 @@ type DockercliLocalConfigConfigWrapperYml struct {
 	configWrapper api_config.ConfigWrapper
 
 	config dockercliLocalConfigureYML
+	loaded bool
+
+	// endpoint is set for the configuration of a named endpoint
+	endpoint     *dockercliLocalConfigureYML_Endpoint
+	endpointName string
 }
 
 // Constructor for DockercliLocalConfigConfigWrapperYml
@@ -44,29 +51,293 @@ func (configYml *DockercliLocalConfigConfigWrapperYml) DockercliLocalConfig() Do
 
 /**
  * DockercliLocalConfig Interface methods
+ *
+ * Any values missing from the yml fall back to the DockercliLocalConfigDefault
  */
 
 func (configYml *DockercliLocalConfigConfigWrapperYml) ClientOptions() *docker_cli_flags.ClientOptions {
-	return docker_cli_flags.NewClientOptions()
+	if configYml.endpoint == nil {
+		return configYml.DockercliLocalConfigDefault.ClientOptions()
+	}
+	opts, err := configYml.endpointClientOptions()
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{"endpoint": configYml.endpointName}).Error("Could not configure dockercli endpoint client")
+	}
+	return opts
 }
 
 func (configYml *DockercliLocalConfigConfigWrapperYml) DeployOptions() *handler_dockercli_stack_imported.DeployOptions {
-	return handler_dockercli_stack_imported.New_DeployOptions(
-		"",    // bundlefile,
-		"",    // composefile,
-		"",    // namespace,
-		false, // sendRegistryAuth,
+	configYml.safe()
+	ymlOptions := configYml.config.DeployOptions
+
+	composefile := ymlOptions.Composefile
+	if composefile == "" && ymlOptions.Bundlefile == "" {
+		composefile = "docker-compose.yml"
+	}
+
+	deployOptions := handler_dockercli_stack_imported.New_DeployOptions(
+		configYml.projectPath(ymlOptions.Bundlefile), // bundlefile,
+		configYml.projectPath(composefile),           // composefile,
+		configYml.projectName(),                      // namespace,
+		ymlOptions.SendRegistryAuth,                  // sendRegistryAuth,
 	)
+	deployOptions.SetHistoryPath(configYml.historyPath())
+	if configYml.config.Mode != "" {
+		deployOptions.SetMode(configYml.config.Mode)
+	}
+	if ymlOptions.Parallelism > 0 {
+		deployOptions.SetParallelism(ymlOptions.Parallelism)
+	}
+	if ymlOptions.UpdateRetries != nil {
+		deployOptions.SetUpdateRetries(*ymlOptions.UpdateRetries)
+	}
+	deployOptions.SetResolveImage(ymlOptions.ResolveImage)
+	deployOptions.SetBuild(ymlOptions.Build)
+	deployOptions.SetMonitorRollout(ymlOptions.MonitorRollout)
+	deployOptions.SetVersionSecrets(ymlOptions.VersionSecrets)
+	if ymlOptions.ConvergeTimeout != "" {
+		if timeout, err := time.ParseDuration(ymlOptions.ConvergeTimeout); err == nil {
+			deployOptions.SetConvergeTimeout(timeout)
+		} else {
+			log.WithError(err).WithFields(log.Fields{"ConvergeTimeout": ymlOptions.ConvergeTimeout}).Error("Invalid dockercli converge timeout")
+		}
+	}
+	if resolver := configYml.registryAuthResolver(); resolver != nil {
+		deployOptions.SetRegistryAuthResolver(resolver)
+	}
+
+	return deployOptions
 }
 
 func (configYml *DockercliLocalConfigConfigWrapperYml) RemoveOptions() *handler_dockercli_stack_imported.RemoveOptions {
-	return handler_dockercli_stack_imported.New_RemoveOptions(
-		"", // namespace,
+	configYml.safe()
+
+	removeOptions := handler_dockercli_stack_imported.New_RemoveOptions(
+		configYml.projectName(), // namespace,
+	)
+	removeOptions.SetVolumes(configYml.config.RemoveOptions.Volumes)
+	if configYml.config.Mode != "" {
+		removeOptions.SetMode(configYml.config.Mode)
+	}
+
+	return removeOptions
+}
+
+func (configYml *DockercliLocalConfigConfigWrapperYml) RollbackOptions() *handler_dockercli_stack_imported.RollbackOptions {
+	return handler_dockercli_stack_imported.New_RollbackOptions(
+		configYml.projectName(), // namespace,
+	)
+}
+
+func (configYml *DockercliLocalConfigConfigWrapperYml) HistoryOptions() *handler_dockercli_stack_imported.HistoryOptions {
+	configYml.safe()
+
+	historyOptions := handler_dockercli_stack_imported.New_HistoryOptions(
+		configYml.historyPath(),                         // historyPath,
+		configYml.projectName(),                         // namespace,
+		configYml.config.DeployOptions.SendRegistryAuth, // sendRegistryAuth,
+	)
+	if resolver := configYml.registryAuthResolver(); resolver != nil {
+		historyOptions.SetRegistryAuthResolver(resolver)
+	}
+
+	return historyOptions
+}
+
+func (configYml *DockercliLocalConfigConfigWrapperYml) ExportOptions() *handler_dockercli_stack_imported.ExportOptions {
+	return handler_dockercli_stack_imported.New_ExportOptions(
+		configYml.projectName(), // namespace,
+		"",                      // composefile, (empty writes to the cli output)
+	)
+}
+
+func (configYml *DockercliLocalConfigConfigWrapperYml) VolumesOptions() *handler_dockercli_stack_imported.VolumesOptions {
+	return handler_dockercli_stack_imported.New_VolumesOptions(
+		configYml.projectName(), // namespace,
+	)
+}
+
+func (configYml *DockercliLocalConfigConfigWrapperYml) LogsOptions() *handler_dockercli_stack_imported.LogsOptions {
+	return handler_dockercli_stack_imported.New_LogsOptions(
+		configYml.projectName(), // namespace,
+	)
+}
+
+func (configYml *DockercliLocalConfigConfigWrapperYml) ExecOptions() *handler_dockercli_stack_imported.ExecOptions {
+	return handler_dockercli_stack_imported.New_ExecOptions(
+		configYml.projectName(), // namespace,
+		true,                    // tty,
+		true,                    // interactive,
+	)
+}
+
+func (configYml *DockercliLocalConfigConfigWrapperYml) RunOptions() *handler_dockercli_stack_imported.RunOptions {
+	configYml.safe()
+
+	runOptions := handler_dockercli_stack_imported.New_RunOptions(
+		configYml.projectName(), // namespace,
+	)
+	runOptions.SetSendRegistryAuth(configYml.config.DeployOptions.SendRegistryAuth)
+	if resolver := configYml.registryAuthResolver(); resolver != nil {
+		runOptions.SetRegistryAuthResolver(resolver)
+	}
+
+	return runOptions
+}
+
+func (configYml *DockercliLocalConfigConfigWrapperYml) ScaleOptions() *handler_dockercli_stack_imported.ScaleOptions {
+	configYml.safe()
+	ymlOptions := configYml.config.DeployOptions
+
+	scaleOptions := handler_dockercli_stack_imported.New_ScaleOptions(
+		configYml.projectName(), // namespace,
 	)
+	scaleOptions.SetWait(configYml.config.ScaleOptions.Wait)
+	if ymlOptions.UpdateRetries != nil {
+		scaleOptions.SetUpdateRetries(*ymlOptions.UpdateRetries)
+	}
+	if timeout, err := time.ParseDuration(ymlOptions.ConvergeTimeout); err == nil {
+		scaleOptions.SetConvergeTimeout(timeout)
+	}
+
+	return scaleOptions
+}
+
+func (configYml *DockercliLocalConfigConfigWrapperYml) StartStopOptions() *handler_dockercli_stack_imported.StartStopOptions {
+	configYml.safe()
+	ymlOptions := configYml.config.DeployOptions
+
+	startStopOptions := handler_dockercli_stack_imported.New_StartStopOptions(
+		configYml.projectName(), // namespace,
+	)
+	startStopOptions.SetWait(configYml.config.StartStopOptions.Wait)
+	if ymlOptions.UpdateRetries != nil {
+		startStopOptions.SetUpdateRetries(*ymlOptions.UpdateRetries)
+	}
+	if timeout, err := time.ParseDuration(ymlOptions.ConvergeTimeout); err == nil {
+		startStopOptions.SetConvergeTimeout(timeout)
+	}
+
+	return startStopOptions
+}
+
+func (configYml *DockercliLocalConfigConfigWrapperYml) BuildOptions() *handler_dockercli_stack_imported.BuildOptions {
+	configYml.safe()
+
+	composefile := configYml.config.DeployOptions.Composefile
+	if composefile == "" {
+		composefile = "docker-compose.yml"
+	}
+
+	return handler_dockercli_stack_imported.New_BuildOptions(
+		configYml.projectPath(composefile), // composefile,
+		configYml.projectName(),            // namespace,
+	)
+}
+
+func (configYml *DockercliLocalConfigConfigWrapperYml) ImagesOptions() *handler_dockercli_stack_imported.ImagesOptions {
+	configYml.safe()
+
+	composefile := configYml.config.DeployOptions.Composefile
+	if composefile == "" {
+		composefile = "docker-compose.yml"
+	}
+
+	imagesOptions := handler_dockercli_stack_imported.New_ImagesOptions(
+		configYml.projectPath(composefile), // composefile,
+		configYml.projectName(),            // namespace,
+	)
+	if configYml.config.ImagesOptions.Parallelism > 0 {
+		imagesOptions.SetParallelism(configYml.config.ImagesOptions.Parallelism)
+	}
+	if resolver := configYml.registryAuthResolver(); resolver != nil {
+		imagesOptions.SetRegistryAuthResolver(resolver)
+	}
+
+	return imagesOptions
+}
+
+func (configYml *DockercliLocalConfigConfigWrapperYml) WatchOptions() *handler_dockercli_stack_imported.WatchOptions {
+	configYml.safe()
+
+	watchOptions := handler_dockercli_stack_imported.New_WatchOptions(*configYml.DeployOptions())
+	if configYml.config.WatchOptions.Debounce != "" {
+		if debounce, err := time.ParseDuration(configYml.config.WatchOptions.Debounce); err == nil {
+			watchOptions.SetDebounce(debounce)
+		} else {
+			log.WithError(err).WithFields(log.Fields{"Debounce": configYml.config.WatchOptions.Debounce}).Error("Invalid dockercli watch debounce")
+		}
+	}
+
+	return watchOptions
+}
+
+func (configYml *DockercliLocalConfigConfigWrapperYml) EventsOptions() *handler_dockercli_stack_imported.EventsOptions {
+	return handler_dockercli_stack_imported.New_EventsOptions(
+		configYml.projectName(), // namespace,
+	)
+}
+
+func (configYml *DockercliLocalConfigConfigWrapperYml) StatusOptions() *handler_dockercli_stack_imported.StatusOptions {
+	configYml.safe()
+
+	statusOptions := handler_dockercli_stack_imported.New_StatusOptions(
+		configYml.projectName(), // namespace,
+	)
+	if configYml.config.StatusOptions.Format != "" {
+		statusOptions.SetFormat(configYml.config.StatusOptions.Format)
+	}
+
+	return statusOptions
 }
 
 func (configYml *DockercliLocalConfigConfigWrapperYml) IO() (io.ReadCloser, io.Writer, io.Writer) {
-	return nil, nil, nil
+	return configYml.DockercliLocalConfigDefault.IO()
+}
+
+// Deploy history path, kept apart for each endpoint
+func (configYml *DockercliLocalConfigConfigWrapperYml) historyPath() string {
+	if configYml.endpoint == nil {
+		return configYml.DockercliLocalConfigDefault.historyPath()
+	}
+	return path.Join(configYml.DockercliLocalConfigDefault.historyPath(), configYml.endpointName)
+}
+
+// Stack namespace, from the yml or the default
+func (configYml *DockercliLocalConfigConfigWrapperYml) projectName() string {
+	configYml.safe()
+	if configYml.config.DeployOptions.Namespace != "" {
+		return configYml.config.DeployOptions.Namespace
+	}
+	return configYml.DockercliLocalConfigDefault.projectName()
+}
+
+// Registry auth from a credentials file or from credentials in the yml, which
+// fall back to the docker cli config if SendRegistryAuth is set
+func (configYml *DockercliLocalConfigConfigWrapperYml) registryAuthResolver() handler_dockercli_stack_imported.RegistryAuthResolver {
+	ymlOptions := configYml.config.DeployOptions
+
+	var fallback handler_dockercli_stack_imported.RegistryAuthResolver
+	if ymlOptions.SendRegistryAuth {
+		fallback = handler_dockercli_stack_imported.New_CliRegistryAuthResolver()
+	}
+
+	switch {
+	case ymlOptions.RegistryCredentialsFile != "":
+		return handler_dockercli_stack_imported.New_RegistryCredentialsFileResolver(configYml.projectPath(ymlOptions.RegistryCredentialsFile), fallback)
+	case len(ymlOptions.RegistryCredentials) > 0:
+		return handler_dockercli_stack_imported.New_RegistryCredentialsResolver(ymlOptions.RegistryCredentials, fallback)
+	default:
+		return nil
+	}
+}
+
+// Make a yml path relative to the project root
+func (configYml *DockercliLocalConfigConfigWrapperYml) projectPath(file string) string {
+	if file == "" || path.IsAbs(file) {
+		return file
+	}
+	return path.Join(configYml.settings.ProjectRootPath, file)
 }
 
 /**
@@ -74,7 +345,8 @@ func (configYml *DockercliLocalConfigConfigWrapperYml) IO() (io.ReadCloser, io.W
  */
 
 func (configYml *DockercliLocalConfigConfigWrapperYml) safe() {
-	if &configYml.config == nil {
+	if !configYml.loaded {
+		configYml.loaded = true
 		if err := configYml.Load(); err != nil {
 			log.WithError(err).Error("Could not load dockercli configuration")
 		}
@@ -119,7 +391,19 @@ func (configYml *DockercliLocalConfigConfigWrapperYml) Save() error {
 
 // Wrapper YML struct for all components that could in the yml file
 type dockercliLocalConfigureYML struct {
-	DeployOptions dockercliLocalConfigureYML_DeployOptions `yml:"Deploy"`
+	// Deploy to a swarm (the default) or "local" to run plain containers without swarm
+	Mode string `yaml:"Mode"`
+
+	DeployOptions    dockercliLocalConfigureYML_DeployOptions    `yaml:"Deploy"`
+	RemoveOptions    dockercliLocalConfigureYML_RemoveOptions    `yaml:"Remove"`
+	ScaleOptions     dockercliLocalConfigureYML_ScaleOptions     `yaml:"Scale"`
+	StartStopOptions dockercliLocalConfigureYML_StartStopOptions `yaml:"StartStop"`
+	ImagesOptions    dockercliLocalConfigureYML_ImagesOptions    `yaml:"Images"`
+	WatchOptions     dockercliLocalConfigureYML_WatchOptions     `yaml:"Watch"`
+	StatusOptions    dockercliLocalConfigureYML_StatusOptions    `yaml:"Status"`
+
+	// Named docker daemons or swarms, such as staging and production
+	Endpoints map[string]dockercliLocalConfigureYML_Endpoint `yaml:"Endpoints"`
 }
 
 // YML holding struct for deploy options, mainly used for the stack handler deploy orchestration
@@ -129,4 +413,75 @@ type dockercliLocalConfigureYML_DeployOptions struct {
 	Composefile      string `yaml:"Composefile"`
 	Namespace        string `yaml:"Namespace"`
 	SendRegistryAuth bool   `yaml:"SendRegistryAuth"`
+	// How many services to create or update at once
+	Parallelism int `yaml:"Parallelism"`
+	// How often to retry a service update that conflicts with another change
+	UpdateRetries *int `yaml:"UpdateRetries"`
+	// Pin service images to their registry digest before deploying
+	ResolveImage bool `yaml:"ResolveImage"`
+	// Build the images of services with a build section before deploying
+	Build bool `yaml:"Build"`
+	// Wait for updated services to roll out, failing if an update pauses or rolls back
+	MonitorRollout bool `yaml:"MonitorRollout"`
+	// Give each secret version a content hashed name, and remove old versions
+	VersionSecrets bool `yaml:"VersionSecrets"`
+	// How long to wait for services to converge, as a duration such as 5m
+	ConvergeTimeout string `yaml:"ConvergeTimeout"`
+	// Registry credentials keyed by registry host, either in a separate file or inline
+	RegistryCredentialsFile string                                                         `yaml:"RegistryCredentialsFile"`
+	RegistryCredentials     map[string]handler_dockercli_stack_imported.RegistryCredential `yaml:"RegistryCredentials"`
+}
+
+// YML holding struct for remove options, used for the stack handler down orchestration
+type dockercliLocalConfigureYML_RemoveOptions struct {
+	// Also remove the stack volumes on the connected node
+	Volumes bool `yaml:"Volumes"`
+}
+
+// YML holding struct for scale options, used for the stack handler scale orchestration
+type dockercliLocalConfigureYML_ScaleOptions struct {
+	// Wait for the scaled services to converge, within the deploy ConvergeTimeout
+	Wait bool `yaml:"Wait"`
+}
+
+// YML holding struct for start and stop options, used for the stack handler start and stop orchestration
+type dockercliLocalConfigureYML_StartStopOptions struct {
+	// Wait for the services to converge, within the deploy ConvergeTimeout
+	Wait bool `yaml:"Wait"`
+}
+
+// YML holding struct for image options, used for the stack handler pull and push operations
+type dockercliLocalConfigureYML_ImagesOptions struct {
+	// How many images to pull or push at once
+	Parallelism int `yaml:"Parallelism"`
+}
+
+// YML holding struct for watch options, used for the stack handler watch orchestration
+type dockercliLocalConfigureYML_WatchOptions struct {
+	// How long to wait after the last file change before redeploying, as a duration such as 2s
+	Debounce string `yaml:"Debounce"`
+}
+
+// YML holding struct for status options, used for the stack handler status monitoring
+type dockercliLocalConfigureYML_StatusOptions struct {
+	// Write the status as a "table" (the default), "json" or "yaml"
+	Format string `yaml:"Format"`
+}
+
+// YML holding struct for a named endpoint, a docker daemon or swarm that the stack can target
+type dockercliLocalConfigureYML_Endpoint struct {
+	// Docker host to connect to, such as tcp://swarm.example.com:2376
+	Host string `yaml:"Host"`
+	// Connect with TLS, and verify the daemon certificate if TLSVerify is set
+	TLS       bool `yaml:"TLS"`
+	TLSVerify bool `yaml:"TLSVerify"`
+	// TLS certificate files, relative to the project root
+	TLSCACert string `yaml:"TLSCACert"`
+	TLSCert   string `yaml:"TLSCert"`
+	TLSKey    string `yaml:"TLSKey"`
+	// Deploy mode for the endpoint, replacing the top level Mode
+	Mode string `yaml:"Mode"`
+	// Deploy options for the endpoint, merged over the top level Deploy options
+	// by key, so that the given keys replace the top level values
+	Deploy map[string]interface{} `yaml:"Deploy"`
 }
@@ -1,6 +1,66 @@
 package local
 
+import (
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_result "github.com/wunderkraut/radi-api/result"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack "github.com/wunderkraut/radi-handler-dockercli/stack"
+	handler_local "github.com/wunderkraut/radi-handlers/local"
+)
+
 /**
- * Some dockercli implementation monitor methods for things like outputting
- * settings for testing
+ * Handler for local monitoring through dockercli, such as following the
+ * logs of the stack services
  */
+
+// Local Handler for monitoring using docker cli
+type DockercliMonitorHandler struct {
+	handler_local.LocalHandler_Base
+	DockercliLocalHandlerBase
+	handler_dockercli.DockercliHandlerBase
+	handler_dockercli_stack.DockercliStackHandlerBase
+}
+
+// Constructor for DockercliMonitorHandler
+func New_DockercliMonitorHandler(localBase *handler_local.LocalHandler_Base, dockerCLIBase *handler_dockercli.DockercliHandlerBase, stackBase *handler_dockercli_stack.DockercliStackHandlerBase) *DockercliMonitorHandler {
+	return &DockercliMonitorHandler{
+		LocalHandler_Base:         *localBase,
+		DockercliHandlerBase:      *dockerCLIBase,
+		DockercliStackHandlerBase: *stackBase,
+	}
+}
+
+// Validate the Base Handler
+func (base *DockercliMonitorHandler) Id() string {
+	return base.endpointId("dockercli.monitor")
+}
+
+// Validate the Base Handler
+func (base *DockercliMonitorHandler) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Validate the Base Handler
+func (base *DockercliMonitorHandler) Operations() api_operation.Operations {
+	ops := api_operation.New_SimpleOperations()
+
+	// use a single base operation
+	baseCliOp := base.DockercliOperationBase()
+	baseStackOp := base.DockercliStackOperationBase()
+
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackLogsOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackEventsOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackStatusOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+
+	return base.endpointOperations(ops.Operations())
+}
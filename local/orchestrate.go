@@ -32,7 +32,7 @@ func New_DockercliOrchestrateHandler(localBase *handler_local.LocalHandler_Base,
 
 // Validate the Base Handler
 func (base *DockercliOrchestrateHandler) Id() string {
-	return "dockercli.orchestrate"
+	return base.endpointId("dockercli.orchestrate")
 }
 
 // Validate the Base Handler
@@ -56,6 +56,62 @@ func (base *DockercliOrchestrateHandler) Operations() api_operation.Operations {
 		DockercliOperationBase:      *baseCliOp,
 		DockercliStackOperationBase: *baseStackOp,
 	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackRollbackOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackHistoryListOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackHistoryShowOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackHistoryDiffOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackHistoryRedeployOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackExportOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackVolumesOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackScaleOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackStopOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackStartOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackBuildOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackPullOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackPushOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
+	ops.Add(api_operation.Operation(&handler_dockercli_stack.DockercliStackWatchOperation{
+		DockercliOperationBase:      *baseCliOp,
+		DockercliStackOperationBase: *baseStackOp,
+	}))
 
-	return ops.Operations()
+	return base.endpointOperations(ops.Operations())
 }
@@ -63,3 +63,101 @@ func (stackBase *DockercliStackOperationBase) RemoveOptionsProperty() *Dockercli
 	remOptsProp.Set(*remOpts)
 	return &remOptsProp
 }
+
+func (stackBase *DockercliStackOperationBase) RollbackOptionsProperty() *DockercliStackRollbackOptionsProperty {
+	rollOpts := stackBase.DockercliStackConfig().RollbackOptions()
+	rollOptsProp := DockercliStackRollbackOptionsProperty{}
+	rollOptsProp.Set(*rollOpts)
+	return &rollOptsProp
+}
+
+func (stackBase *DockercliStackOperationBase) HistoryOptionsProperty() *DockercliStackHistoryOptionsProperty {
+	histOpts := stackBase.DockercliStackConfig().HistoryOptions()
+	histOptsProp := DockercliStackHistoryOptionsProperty{}
+	histOptsProp.Set(*histOpts)
+	return &histOptsProp
+}
+
+func (stackBase *DockercliStackOperationBase) ExportOptionsProperty() *DockercliStackExportOptionsProperty {
+	expOpts := stackBase.DockercliStackConfig().ExportOptions()
+	expOptsProp := DockercliStackExportOptionsProperty{}
+	expOptsProp.Set(*expOpts)
+	return &expOptsProp
+}
+
+func (stackBase *DockercliStackOperationBase) VolumesOptionsProperty() *DockercliStackVolumesOptionsProperty {
+	volOpts := stackBase.DockercliStackConfig().VolumesOptions()
+	volOptsProp := DockercliStackVolumesOptionsProperty{}
+	volOptsProp.Set(*volOpts)
+	return &volOptsProp
+}
+
+func (stackBase *DockercliStackOperationBase) LogsOptionsProperty() *DockercliStackLogsOptionsProperty {
+	logsOpts := stackBase.DockercliStackConfig().LogsOptions()
+	logsOptsProp := DockercliStackLogsOptionsProperty{}
+	logsOptsProp.Set(*logsOpts)
+	return &logsOptsProp
+}
+
+func (stackBase *DockercliStackOperationBase) ExecOptionsProperty() *DockercliStackExecOptionsProperty {
+	execOpts := stackBase.DockercliStackConfig().ExecOptions()
+	execOptsProp := DockercliStackExecOptionsProperty{}
+	execOptsProp.Set(*execOpts)
+	return &execOptsProp
+}
+
+func (stackBase *DockercliStackOperationBase) RunOptionsProperty() *DockercliStackRunOptionsProperty {
+	runOpts := stackBase.DockercliStackConfig().RunOptions()
+	runOptsProp := DockercliStackRunOptionsProperty{}
+	runOptsProp.Set(*runOpts)
+	return &runOptsProp
+}
+
+func (stackBase *DockercliStackOperationBase) ScaleOptionsProperty() *DockercliStackScaleOptionsProperty {
+	scaleOpts := stackBase.DockercliStackConfig().ScaleOptions()
+	scaleOptsProp := DockercliStackScaleOptionsProperty{}
+	scaleOptsProp.Set(*scaleOpts)
+	return &scaleOptsProp
+}
+
+func (stackBase *DockercliStackOperationBase) StartStopOptionsProperty() *DockercliStackStartStopOptionsProperty {
+	startStopOpts := stackBase.DockercliStackConfig().StartStopOptions()
+	startStopOptsProp := DockercliStackStartStopOptionsProperty{}
+	startStopOptsProp.Set(*startStopOpts)
+	return &startStopOptsProp
+}
+
+func (stackBase *DockercliStackOperationBase) BuildOptionsProperty() *DockercliStackBuildOptionsProperty {
+	buildOpts := stackBase.DockercliStackConfig().BuildOptions()
+	buildOptsProp := DockercliStackBuildOptionsProperty{}
+	buildOptsProp.Set(*buildOpts)
+	return &buildOptsProp
+}
+
+func (stackBase *DockercliStackOperationBase) ImagesOptionsProperty() *DockercliStackImagesOptionsProperty {
+	imagesOpts := stackBase.DockercliStackConfig().ImagesOptions()
+	imagesOptsProp := DockercliStackImagesOptionsProperty{}
+	imagesOptsProp.Set(*imagesOpts)
+	return &imagesOptsProp
+}
+
+func (stackBase *DockercliStackOperationBase) WatchOptionsProperty() *DockercliStackWatchOptionsProperty {
+	watchOpts := stackBase.DockercliStackConfig().WatchOptions()
+	watchOptsProp := DockercliStackWatchOptionsProperty{}
+	watchOptsProp.Set(*watchOpts)
+	return &watchOptsProp
+}
+
+func (stackBase *DockercliStackOperationBase) EventsOptionsProperty() *DockercliStackEventsOptionsProperty {
+	eventsOpts := stackBase.DockercliStackConfig().EventsOptions()
+	eventsOptsProp := DockercliStackEventsOptionsProperty{}
+	eventsOptsProp.Set(*eventsOpts)
+	return &eventsOptsProp
+}
+
+func (stackBase *DockercliStackOperationBase) StatusOptionsProperty() *DockercliStackStatusOptionsProperty {
+	statusOpts := stackBase.DockercliStackConfig().StatusOptions()
+	statusOptsProp := DockercliStackStatusOptionsProperty{}
+	statusOptsProp.Set(*statusOpts)
+	return &statusOptsProp
+}
@@ -0,0 +1,106 @@
+package stack
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_BUILD = "dockercli.stack.build"
+)
+
+/**
+ * Build operation
+ */
+
+// Operation that builds the images of stack services with a build section
+type DockercliStackBuildOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (build *DockercliStackBuildOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_BUILD
+}
+
+// Label the operation
+func (build *DockercliStackBuildOperation) Label() string {
+	return "Build"
+}
+
+// Description for the operation
+func (build *DockercliStackBuildOperation) Description() string {
+	return "Build the images of the stack services which have a build section."
+}
+
+// Man page for the operation
+func (build *DockercliStackBuildOperation) Help() string {
+	return "Services without an image name are tagged as <namespace>_<service>:latest, which only exists on the connected engine.  Swarm deploys therefore need an image name for every service with a build section, and a deploy with Build set pushes the built images to their registry.  Local deploys do not push."
+}
+
+// Define the operations as externally used
+func (build *DockercliStackBuildOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (build *DockercliStackBuildOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use a build Opts property, with a default set to the configured BuildOptions
+	props.Add(api_property.Property(build.BuildOptionsProperty()))
+	// Per service outcomes are passed back in this property
+	props.Add(api_property.Property(&DockercliStackServiceResultsProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (build *DockercliStackBuildOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (build *DockercliStackBuildOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_BUILDOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.BuildOptions)
+
+		cli := build.DockerCli()
+
+		log.WithFields(log.Fields{"BuildOptions": opts}).Info("Running Build using docker cli stack")
+
+		results, err := handler_dockercli_stack_imported.RunBuild(cli, opts)
+
+		for _, result := range results {
+			if result.Error == nil {
+				log.WithFields(log.Fields{"service": result.Name, "id": result.ID}).Info("Built service image")
+			} else {
+				log.WithError(result.Error).WithFields(log.Fields{"service": result.Name, "id": result.ID}).Error("Failed to build service image")
+			}
+		}
+		if resultsProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_SERVICERESULTS_KEY); found {
+			resultsProp.Set(results)
+		}
+
+		if err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
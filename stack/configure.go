@@ -11,4 +11,18 @@ import (
 type DockercliStackConfig interface {
 	DeployOptions() *handler_dockercli_stack_imported.DeployOptions
 	RemoveOptions() *handler_dockercli_stack_imported.RemoveOptions
+	RollbackOptions() *handler_dockercli_stack_imported.RollbackOptions
+	HistoryOptions() *handler_dockercli_stack_imported.HistoryOptions
+	ExportOptions() *handler_dockercli_stack_imported.ExportOptions
+	VolumesOptions() *handler_dockercli_stack_imported.VolumesOptions
+	LogsOptions() *handler_dockercli_stack_imported.LogsOptions
+	ExecOptions() *handler_dockercli_stack_imported.ExecOptions
+	RunOptions() *handler_dockercli_stack_imported.RunOptions
+	ScaleOptions() *handler_dockercli_stack_imported.ScaleOptions
+	StartStopOptions() *handler_dockercli_stack_imported.StartStopOptions
+	BuildOptions() *handler_dockercli_stack_imported.BuildOptions
+	ImagesOptions() *handler_dockercli_stack_imported.ImagesOptions
+	WatchOptions() *handler_dockercli_stack_imported.WatchOptions
+	EventsOptions() *handler_dockercli_stack_imported.EventsOptions
+	StatusOptions() *handler_dockercli_stack_imported.StatusOptions
 }
@@ -0,0 +1,96 @@
+package stack
+
+import (
+	"context"
+
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_EVENTS = "dockercli.stack.monitor.events"
+)
+
+// Operation that streams the engine events of the stack
+type DockercliStackEventsOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (events *DockercliStackEventsOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_EVENTS
+}
+
+// Label the operation
+func (events *DockercliStackEventsOperation) Label() string {
+	return "Events"
+}
+
+// Description for the operation
+func (events *DockercliStackEventsOperation) Description() string {
+	return "Stream service, task, container, network and secret events of the stack."
+}
+
+// Man page for the operation
+func (events *DockercliStackEventsOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (events *DockercliStackEventsOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (events *DockercliStackEventsOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use an events Opts property, with a default set to the configured EventsOptions
+	props.Add(api_property.Property(events.EventsOptionsProperty()))
+	// Cancelling this context stops streaming events
+	props.Add(api_property.Property(&DockercliStackContextProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (events *DockercliStackEventsOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (events *DockercliStackEventsOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_EVENTSOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.EventsOptions)
+
+		ctx := context.Background()
+		if ctxProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_CONTEXT_KEY); found {
+			ctx = ctxProp.Get().(context.Context)
+		}
+
+		cli := events.DockerCli()
+
+		log.WithFields(log.Fields{"EventsOptions": opts}).Info("Running Events using docker cli stack")
+
+		if err := handler_dockercli_stack_imported.RunEvents(ctx, cli, opts); err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
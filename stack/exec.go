@@ -0,0 +1,126 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_EXEC = "dockercli.stack.command.exec"
+)
+
+/**
+ * Command operations
+ */
+
+// Operation that executes a command in a running task of a stack service
+type DockercliStackExecOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (exec *DockercliStackExecOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_EXEC
+}
+
+// Label the operation
+func (exec *DockercliStackExecOperation) Label() string {
+	return "Exec"
+}
+
+// Description for the operation
+func (exec *DockercliStackExecOperation) Description() string {
+	return "Execute a command in a running container of a stack service."
+}
+
+// Man page for the operation
+func (exec *DockercliStackExecOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (exec *DockercliStackExecOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (exec *DockercliStackExecOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use an exec Opts property, with a default set to the configured ExecOptions
+	props.Add(api_property.Property(exec.ExecOptionsProperty()))
+	// The service and command to exec
+	props.Add(api_property.Property(&DockercliStackServiceProperty{}))
+	props.Add(api_property.Property(&DockercliStackCommandProperty{}))
+	// The exit code of the command is passed back in this property
+	props.Add(api_property.Property(&DockercliStackExitCodeProperty{}))
+	// Cancelling this context detaches from the command
+	props.Add(api_property.Property(&DockercliStackContextProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (exec *DockercliStackExecOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (exec *DockercliStackExecOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_EXECOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.ExecOptions)
+
+		if serviceProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_SERVICE_KEY); found {
+			if service := serviceProp.Get().(string); service != "" {
+				opts.SetService(service)
+			}
+		}
+		if commandProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_COMMAND_KEY); found {
+			if command := commandProp.Get().([]string); len(command) > 0 {
+				opts.SetCommand(command)
+			}
+		}
+
+		ctx := context.Background()
+		if ctxProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_CONTEXT_KEY); found {
+			ctx = ctxProp.Get().(context.Context)
+		}
+
+		cli := exec.DockerCli()
+
+		log.WithFields(log.Fields{"ExecOptions": opts}).Info("Running Exec using docker cli stack")
+
+		exitCode, err := handler_dockercli_stack_imported.RunExec(ctx, cli, opts)
+		if err == nil && exitCode != 0 {
+			err = fmt.Errorf("Command exited with status %d", exitCode)
+		}
+
+		if exitCodeProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_EXITCODE_KEY); found {
+			exitCodeProp.Set(exitCode)
+		}
+
+		if err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
@@ -0,0 +1,91 @@
+package stack
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_EXPORT = "dockercli.stack.export"
+)
+
+/**
+ * Export operation
+ */
+
+// Operation that writes a running stack back out as a compose file
+type DockercliStackExportOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (export *DockercliStackExportOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_EXPORT
+}
+
+// Label the operation
+func (export *DockercliStackExportOperation) Label() string {
+	return "Export"
+}
+
+// Description for the operation
+func (export *DockercliStackExportOperation) Description() string {
+	return "Export the running stack services, networks, secrets and configs as a compose file."
+}
+
+// Man page for the operation
+func (export *DockercliStackExportOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (export *DockercliStackExportOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (export *DockercliStackExportOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use an export Opts property, with a default set to the configured ExportOptions
+	props.Add(api_property.Property(export.ExportOptionsProperty()))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (export *DockercliStackExportOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (export *DockercliStackExportOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_EXPORTOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.ExportOptions)
+
+		cli := export.DockerCli()
+
+		log.WithFields(log.Fields{"ExportOptions": opts}).Info("Running Export using docker cli stack")
+
+		if err := handler_dockercli_stack_imported.RunExport(cli, opts); err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
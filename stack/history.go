@@ -0,0 +1,306 @@
+package stack
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_HISTORY_LIST     = "dockercli.stack.history.list"
+	OPERATION_ID_DOCKERCLI_STACK_HISTORY_SHOW     = "dockercli.stack.history.show"
+	OPERATION_ID_DOCKERCLI_STACK_HISTORY_DIFF     = "dockercli.stack.history.diff"
+	OPERATION_ID_DOCKERCLI_STACK_HISTORY_REDEPLOY = "dockercli.stack.history.redeploy"
+)
+
+/**
+ * Deploy history operations
+ */
+
+// Operation that lists recorded deploy snapshots
+type DockercliStackHistoryListOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (list *DockercliStackHistoryListOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_HISTORY_LIST
+}
+
+// Label the operation
+func (list *DockercliStackHistoryListOperation) Label() string {
+	return "Deploy history"
+}
+
+// Description for the operation
+func (list *DockercliStackHistoryListOperation) Description() string {
+	return "List the recorded deploy snapshots for the stack."
+}
+
+// Man page for the operation
+func (list *DockercliStackHistoryListOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (list *DockercliStackHistoryListOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (list *DockercliStackHistoryListOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	props.Add(api_property.Property(list.HistoryOptionsProperty()))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (list *DockercliStackHistoryListOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (list *DockercliStackHistoryListOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_HISTORYOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.HistoryOptions)
+
+		cli := list.DockerCli()
+
+		if err := handler_dockercli_stack_imported.RunHistoryList(cli, opts); err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
+
+// Operation that shows a single deploy snapshot
+type DockercliStackHistoryShowOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (show *DockercliStackHistoryShowOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_HISTORY_SHOW
+}
+
+// Label the operation
+func (show *DockercliStackHistoryShowOperation) Label() string {
+	return "Show deploy snapshot"
+}
+
+// Description for the operation
+func (show *DockercliStackHistoryShowOperation) Description() string {
+	return "Show the config, specs and images recorded in a deploy snapshot."
+}
+
+// Man page for the operation
+func (show *DockercliStackHistoryShowOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (show *DockercliStackHistoryShowOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (show *DockercliStackHistoryShowOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	props.Add(api_property.Property(show.HistoryOptionsProperty()))
+	props.Add(api_property.Property(&DockercliStackSnapshotProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (show *DockercliStackHistoryShowOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (show *DockercliStackHistoryShowOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_HISTORYOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.HistoryOptions)
+		snapshotProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_SNAPSHOT_KEY)
+		snapshot := snapshotProp.Get().(string)
+
+		cli := show.DockerCli()
+
+		if err := handler_dockercli_stack_imported.RunHistoryShow(cli, opts, snapshot); err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
+
+// Operation that compares two deploy snapshots
+type DockercliStackHistoryDiffOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (diff *DockercliStackHistoryDiffOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_HISTORY_DIFF
+}
+
+// Label the operation
+func (diff *DockercliStackHistoryDiffOperation) Label() string {
+	return "Diff deploy snapshots"
+}
+
+// Description for the operation
+func (diff *DockercliStackHistoryDiffOperation) Description() string {
+	return "Show the differences between two deploy snapshots."
+}
+
+// Man page for the operation
+func (diff *DockercliStackHistoryDiffOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (diff *DockercliStackHistoryDiffOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (diff *DockercliStackHistoryDiffOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	props.Add(api_property.Property(diff.HistoryOptionsProperty()))
+	props.Add(api_property.Property(&DockercliStackSnapshotFromProperty{}))
+	props.Add(api_property.Property(&DockercliStackSnapshotProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (diff *DockercliStackHistoryDiffOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (diff *DockercliStackHistoryDiffOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_HISTORYOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.HistoryOptions)
+		fromProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_SNAPSHOTFROM_KEY)
+		from := fromProp.Get().(string)
+		toProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_SNAPSHOT_KEY)
+		to := toProp.Get().(string)
+
+		cli := diff.DockerCli()
+
+		if err := handler_dockercli_stack_imported.RunHistoryDiff(cli, opts, from, to); err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
+
+// Operation that redeploys a prior deploy snapshot
+type DockercliStackHistoryRedeployOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (redeploy *DockercliStackHistoryRedeployOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_HISTORY_REDEPLOY
+}
+
+// Label the operation
+func (redeploy *DockercliStackHistoryRedeployOperation) Label() string {
+	return "Redeploy snapshot"
+}
+
+// Description for the operation
+func (redeploy *DockercliStackHistoryRedeployOperation) Description() string {
+	return "Redeploy the exact service specs and images of a deploy snapshot."
+}
+
+// Man page for the operation
+func (redeploy *DockercliStackHistoryRedeployOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (redeploy *DockercliStackHistoryRedeployOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (redeploy *DockercliStackHistoryRedeployOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	props.Add(api_property.Property(redeploy.HistoryOptionsProperty()))
+	props.Add(api_property.Property(&DockercliStackSnapshotProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (redeploy *DockercliStackHistoryRedeployOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (redeploy *DockercliStackHistoryRedeployOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_HISTORYOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.HistoryOptions)
+		snapshotProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_SNAPSHOT_KEY)
+		snapshot := snapshotProp.Get().(string)
+
+		cli := redeploy.DockerCli()
+
+		log.WithFields(log.Fields{"HistoryOptions": opts, "snapshot": snapshot}).Info("Redeploying stack snapshot using docker cli stack")
+
+		if err := handler_dockercli_stack_imported.RunHistoryRedeploy(cli, opts, snapshot); err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
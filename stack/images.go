@@ -0,0 +1,212 @@
+package stack
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_PULL = "dockercli.stack.image.pull"
+	OPERATION_ID_DOCKERCLI_STACK_PUSH = "dockercli.stack.image.push"
+)
+
+/**
+ * Pull operation
+ */
+
+// Operation that pulls the images of the stack services
+type DockercliStackPullOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (pull *DockercliStackPullOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_PULL
+}
+
+// Label the operation
+func (pull *DockercliStackPullOperation) Label() string {
+	return "Pull"
+}
+
+// Description for the operation
+func (pull *DockercliStackPullOperation) Description() string {
+	return "Pull the images of all stack services, to the engine that the client is connected to."
+}
+
+// Man page for the operation
+func (pull *DockercliStackPullOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (pull *DockercliStackPullOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (pull *DockercliStackPullOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use an images Opts property, with a default set to the configured ImagesOptions
+	props.Add(api_property.Property(pull.ImagesOptionsProperty()))
+	// Per image outcomes are passed back in this property
+	props.Add(api_property.Property(&DockercliStackImageResultsProperty{}))
+	// Per image progress events are passed to the handler in this property, if one is set
+	props.Add(api_property.Property(&DockercliStackImageEventsProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (pull *DockercliStackPullOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (pull *DockercliStackPullOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_IMAGESOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.ImagesOptions)
+
+		cli := pull.DockerCli()
+
+		if eventsProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_IMAGEEVENTS_KEY); found {
+			if events, ok := eventsProp.Get().(func(handler_dockercli_stack_imported.ImageEvent)); ok && events != nil {
+				opts.SetEvents(events)
+			}
+		}
+
+		log.WithFields(log.Fields{"ImagesOptions": opts}).Info("Running Pull using docker cli stack")
+
+		results, err := handler_dockercli_stack_imported.RunPull(cli, opts)
+
+		for _, result := range results {
+			if result.Error == nil {
+				log.WithFields(log.Fields{"image": result.Image, "services": result.Services}).Info("Pulled image")
+			} else {
+				log.WithError(result.Error).WithFields(log.Fields{"image": result.Image, "services": result.Services}).Error("Failed to pull image")
+			}
+		}
+		if resultsProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_IMAGERESULTS_KEY); found {
+			resultsProp.Set(results)
+		}
+
+		if err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
+
+/**
+ * Push operation
+ */
+
+// Operation that pushes the images of the stack services
+type DockercliStackPushOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (push *DockercliStackPushOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_PUSH
+}
+
+// Label the operation
+func (push *DockercliStackPushOperation) Label() string {
+	return "Push"
+}
+
+// Description for the operation
+func (push *DockercliStackPushOperation) Description() string {
+	return "Push the images of all stack services to their registries."
+}
+
+// Man page for the operation
+func (push *DockercliStackPushOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (push *DockercliStackPushOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (push *DockercliStackPushOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use an images Opts property, with a default set to the configured ImagesOptions
+	props.Add(api_property.Property(push.ImagesOptionsProperty()))
+	// Per image outcomes are passed back in this property
+	props.Add(api_property.Property(&DockercliStackImageResultsProperty{}))
+	// Per image progress events are passed to the handler in this property, if one is set
+	props.Add(api_property.Property(&DockercliStackImageEventsProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (push *DockercliStackPushOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (push *DockercliStackPushOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_IMAGESOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.ImagesOptions)
+
+		cli := push.DockerCli()
+
+		if eventsProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_IMAGEEVENTS_KEY); found {
+			if events, ok := eventsProp.Get().(func(handler_dockercli_stack_imported.ImageEvent)); ok && events != nil {
+				opts.SetEvents(events)
+			}
+		}
+
+		log.WithFields(log.Fields{"ImagesOptions": opts}).Info("Running Push using docker cli stack")
+
+		results, err := handler_dockercli_stack_imported.RunPush(cli, opts)
+
+		for _, result := range results {
+			if result.Error == nil {
+				log.WithFields(log.Fields{"image": result.Image, "services": result.Services}).Info("Pushed image")
+			} else {
+				log.WithError(result.Error).WithFields(log.Fields{"image": result.Image, "services": result.Services}).Error("Failed to push image")
+			}
+		}
+		if resultsProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_IMAGERESULTS_KEY); found {
+			resultsProp.Set(results)
+		}
+
+		if err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
@@ -0,0 +1,100 @@
+package stack
+
+import (
+	"context"
+
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_LOGS = "dockercli.stack.monitor.logs"
+)
+
+/**
+ * Monitor operations
+ */
+
+// Operation that streams the logs of the stack services
+type DockercliStackLogsOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (logs *DockercliStackLogsOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_LOGS
+}
+
+// Label the operation
+func (logs *DockercliStackLogsOperation) Label() string {
+	return "Logs"
+}
+
+// Description for the operation
+func (logs *DockercliStackLogsOperation) Description() string {
+	return "Stream the logs of the stack services, or of one service."
+}
+
+// Man page for the operation
+func (logs *DockercliStackLogsOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (logs *DockercliStackLogsOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (logs *DockercliStackLogsOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use a logs Opts property, with a default set to the configured LogsOptions
+	props.Add(api_property.Property(logs.LogsOptionsProperty()))
+	// Cancelling this context stops following the logs
+	props.Add(api_property.Property(&DockercliStackContextProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (logs *DockercliStackLogsOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (logs *DockercliStackLogsOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_LOGSOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.LogsOptions)
+
+		ctx := context.Background()
+		if ctxProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_CONTEXT_KEY); found {
+			ctx = ctxProp.Get().(context.Context)
+		}
+
+		cli := logs.DockerCli()
+
+		log.WithFields(log.Fields{"LogsOptions": opts}).Info("Running Logs using docker cli stack")
+
+		if err := handler_dockercli_stack_imported.RunLogs(ctx, cli, opts); err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
@@ -34,6 +34,11 @@ func (down *DockercliStackOrchestrateDownOperation) Id() string {
 	return OPERATION_ID_DOCKERCLI_STACK_DOWN
 }
 
+// Description for the operation
+func (down *DockercliStackOrchestrateDownOperation) Description() string {
+	return "Remove the stack services, networks, secrets and configs, and optionally the stack volumes on the connected node only."
+}
+
 // Define the operations as externally used
 func (down *DockercliStackOrchestrateDownOperation) Usage() api_usage.Usage {
 	return api_operation.Usage_External()
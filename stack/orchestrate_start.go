@@ -0,0 +1,106 @@
+package stack
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_START = "dockercli.stack.orchestrate.start"
+)
+
+/**
+ * Start operation
+ */
+
+// Operation that starts the stack services, keeping the rest of the stack
+type DockercliStackStartOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (start *DockercliStackStartOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_START
+}
+
+// Label the operation
+func (start *DockercliStackStartOperation) Label() string {
+	return "Start"
+}
+
+// Description for the operation
+func (start *DockercliStackStartOperation) Description() string {
+	return "Start stopped stack services again, with their previous replica counts."
+}
+
+// Man page for the operation
+func (start *DockercliStackStartOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (start *DockercliStackStartOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (start *DockercliStackStartOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use a start/stop Opts property, with a default set to the configured StartStopOptions
+	props.Add(api_property.Property(start.StartStopOptionsProperty()))
+	// Per service outcomes are passed back in this property
+	props.Add(api_property.Property(&DockercliStackServiceResultsProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (start *DockercliStackStartOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (start *DockercliStackStartOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_STARTSTOPOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.StartStopOptions)
+
+		cli := start.DockerCli()
+
+		log.WithFields(log.Fields{"StartStopOptions": opts}).Info("Running Start orchestration using docker cli stack")
+
+		results, err := handler_dockercli_stack_imported.RunStart(cli, opts)
+
+		for _, result := range results {
+			if result.Error == nil {
+				log.WithFields(log.Fields{"service": result.Name, "id": result.ID, "action": result.Action}).Info("Started service")
+			} else {
+				log.WithError(result.Error).WithFields(log.Fields{"service": result.Name, "id": result.ID}).Error("Failed to start service")
+			}
+		}
+		if resultsProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_SERVICERESULTS_KEY); found {
+			resultsProp.Set(results)
+		}
+
+		if err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
@@ -0,0 +1,106 @@
+package stack
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_STOP = "dockercli.stack.orchestrate.stop"
+)
+
+/**
+ * Stop operation
+ */
+
+// Operation that stops the stack services, keeping the rest of the stack
+type DockercliStackStopOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (stop *DockercliStackStopOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_STOP
+}
+
+// Label the operation
+func (stop *DockercliStackStopOperation) Label() string {
+	return "Stop"
+}
+
+// Description for the operation
+func (stop *DockercliStackStopOperation) Description() string {
+	return "Stop the stack services by scaling them to zero, keeping networks, secrets and volumes."
+}
+
+// Man page for the operation
+func (stop *DockercliStackStopOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (stop *DockercliStackStopOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (stop *DockercliStackStopOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use a start/stop Opts property, with a default set to the configured StartStopOptions
+	props.Add(api_property.Property(stop.StartStopOptionsProperty()))
+	// Per service outcomes are passed back in this property
+	props.Add(api_property.Property(&DockercliStackServiceResultsProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (stop *DockercliStackStopOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (stop *DockercliStackStopOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_STARTSTOPOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.StartStopOptions)
+
+		cli := stop.DockerCli()
+
+		log.WithFields(log.Fields{"StartStopOptions": opts}).Info("Running Stop orchestration using docker cli stack")
+
+		results, err := handler_dockercli_stack_imported.RunStop(cli, opts)
+
+		for _, result := range results {
+			if result.Error == nil {
+				log.WithFields(log.Fields{"service": result.Name, "id": result.ID, "action": result.Action}).Info("Stopped service")
+			} else {
+				log.WithError(result.Error).WithFields(log.Fields{"service": result.Name, "id": result.ID}).Error("Failed to stop service")
+			}
+		}
+		if resultsProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_SERVICERESULTS_KEY); found {
+			resultsProp.Set(results)
+		}
+
+		if err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
@@ -45,6 +45,8 @@ func (up *DockercliStackOrchestrateUpOperation) Properties() api_property.Proper
 
 	// Use a deploy Opts propperty, with a default set to the configured DeployOptis
 	props.Add(api_property.Property(up.DeployOptionsProperty()))
+	// Per service outcomes are passed back in this property
+	props.Add(api_property.Property(&DockercliStackServiceResultsProperty{}))
 
 	return props.Properties()
 }
@@ -66,7 +68,25 @@ func (up *DockercliStackOrchestrateUpOperation) Exec(props api_property.Properti
 
 		log.WithFields(log.Fields{"DeployOptions": opts}).Info("Running Up orchestration using docker cli stack")
 
-		if err := handler_dockercli_stack_imported.RunDeploy(cli, opts); err == nil {
+		result, err := handler_dockercli_stack_imported.RunDeploy(cli, opts)
+
+		if result != nil {
+			for _, service := range result.Services {
+				if service.Error == nil {
+					log.WithFields(log.Fields{"service": service.Name, "id": service.ID, "action": service.Action, "retries": service.Retries, "rollout": service.Rollout}).Info("Deployed service")
+				} else {
+					log.WithError(service.Error).WithFields(log.Fields{"service": service.Name, "id": service.ID, "action": service.Action, "retries": service.Retries, "rollout": service.Rollout}).Error("Failed to deploy service")
+				}
+			}
+			for image, pinned := range result.Images {
+				log.WithFields(log.Fields{"image": image, "pinned": pinned}).Info("Pinned service image")
+			}
+			if resultsProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_SERVICERESULTS_KEY); found {
+				resultsProp.Set(result.Services)
+			}
+		}
+
+		if err == nil {
 			res.MarkSuccess()
 		} else {
 			res.AddError(err)
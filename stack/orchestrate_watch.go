@@ -0,0 +1,100 @@
+package stack
+
+import (
+	"context"
+
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_WATCH = "dockercli.stack.orchestrate.watch"
+)
+
+/**
+ * Watch operation
+ */
+
+// Operation that redeploys the stack whenever its compose files change
+type DockercliStackWatchOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (watch *DockercliStackWatchOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_WATCH
+}
+
+// Label the operation
+func (watch *DockercliStackWatchOperation) Label() string {
+	return "Watch"
+}
+
+// Description for the operation
+func (watch *DockercliStackWatchOperation) Description() string {
+	return "Deploy the stack, and redeploy it whenever the compose file or its env files change."
+}
+
+// Man page for the operation
+func (watch *DockercliStackWatchOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (watch *DockercliStackWatchOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (watch *DockercliStackWatchOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use a watch Opts property, with a default set to the configured WatchOptions
+	props.Add(api_property.Property(watch.WatchOptionsProperty()))
+	// Cancelling this context stops watching
+	props.Add(api_property.Property(&DockercliStackContextProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (watch *DockercliStackWatchOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (watch *DockercliStackWatchOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_WATCHOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.WatchOptions)
+
+		ctx := context.Background()
+		if ctxProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_CONTEXT_KEY); found {
+			ctx = ctxProp.Get().(context.Context)
+		}
+
+		cli := watch.DockerCli()
+
+		log.WithFields(log.Fields{"WatchOptions": opts}).Info("Running Watch orchestration using docker cli stack")
+
+		if err := handler_dockercli_stack_imported.RunWatch(ctx, cli, opts); err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
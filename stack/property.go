@@ -1,6 +1,8 @@
 package stack
 
 import (
+	"context"
+
 	log "github.com/Sirupsen/logrus"
 
 	api_property "github.com/wunderkraut/radi-api/property"
@@ -10,8 +12,33 @@ import (
 )
 
 const (
-	OPERATION_PROPERTY_DOCKER_STACK_DEPLOYOPTIONS_KEY = "docker.cli.command.stack.deployoptions"
-	OPERATION_PROPERTY_DOCKER_STACK_REMOVEOPTIONS_KEY = "docker.cli.command.stack.removeoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_DEPLOYOPTIONS_KEY    = "docker.cli.command.stack.deployoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_REMOVEOPTIONS_KEY    = "docker.cli.command.stack.removeoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_ROLLBACKOPTIONS_KEY  = "docker.cli.command.stack.rollbackoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_SERVICERESULTS_KEY   = "docker.cli.command.stack.serviceresults"
+	OPERATION_PROPERTY_DOCKER_STACK_HISTORYOPTIONS_KEY   = "docker.cli.command.stack.historyoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_SNAPSHOT_KEY         = "docker.cli.command.stack.history.snapshot"
+	OPERATION_PROPERTY_DOCKER_STACK_SNAPSHOTFROM_KEY     = "docker.cli.command.stack.history.from"
+	OPERATION_PROPERTY_DOCKER_STACK_EXPORTOPTIONS_KEY    = "docker.cli.command.stack.exportoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_VOLUMESOPTIONS_KEY   = "docker.cli.command.stack.volumesoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_CONTEXT_KEY          = "docker.cli.command.stack.context"
+	OPERATION_PROPERTY_DOCKER_STACK_LOGSOPTIONS_KEY      = "docker.cli.command.stack.logsoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_SERVICE_KEY          = "docker.cli.command.stack.service"
+	OPERATION_PROPERTY_DOCKER_STACK_COMMAND_KEY          = "docker.cli.command.stack.command"
+	OPERATION_PROPERTY_DOCKER_STACK_EXITCODE_KEY         = "docker.cli.command.stack.exitcode"
+	OPERATION_PROPERTY_DOCKER_STACK_EXECOPTIONS_KEY      = "docker.cli.command.stack.execoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_RUNOPTIONS_KEY       = "docker.cli.command.stack.runoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_SCALEOPTIONS_KEY     = "docker.cli.command.stack.scaleoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_SCALES_KEY           = "docker.cli.command.stack.scales"
+	OPERATION_PROPERTY_DOCKER_STACK_STARTSTOPOPTIONS_KEY = "docker.cli.command.stack.startstopoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_BUILDOPTIONS_KEY     = "docker.cli.command.stack.buildoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_IMAGESOPTIONS_KEY    = "docker.cli.command.stack.imagesoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_IMAGERESULTS_KEY     = "docker.cli.command.stack.imageresults"
+	OPERATION_PROPERTY_DOCKER_STACK_IMAGEEVENTS_KEY      = "docker.cli.command.stack.imageevents"
+	OPERATION_PROPERTY_DOCKER_STACK_WATCHOPTIONS_KEY     = "docker.cli.command.stack.watchoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_EVENTSOPTIONS_KEY    = "docker.cli.command.stack.eventsoptions"
+	OPERATION_PROPERTY_DOCKER_STACK_STATUS_KEY           = "docker.cli.command.stack.status"
+	OPERATION_PROPERTY_DOCKER_STACK_STATUSOPTIONS_KEY    = "docker.cli.command.stack.statusoptions"
 )
 
 type DockercliStackDeployOptionsProperty struct {
@@ -113,3 +140,1263 @@ func (remOpts *DockercliStackRemoveOptionsProperty) Copy() api_property.Property
 	prop.Set(remOpts.Get())
 	return api_property.Property(prop)
 }
+
+type DockercliStackRollbackOptionsProperty struct {
+	value handler_dockercli_stack_imported.RollbackOptions
+}
+
+// Id for the property
+func (rollOpts *DockercliStackRollbackOptionsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_ROLLBACKOPTIONS_KEY
+}
+
+// Id for the property
+func (rollOpts *DockercliStackRollbackOptionsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.RollbackOptions"
+}
+
+// Label for the property
+func (rollOpts *DockercliStackRollbackOptionsProperty) Label() string {
+	return "Docker:Stack: Rollback options."
+}
+
+// Description for the property
+func (rollOpts *DockercliStackRollbackOptionsProperty) Description() string {
+	return "Rollback options for a docker stack command"
+}
+
+// Is the Property internal only
+func (rollOpts *DockercliStackRollbackOptionsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (rollOpts *DockercliStackRollbackOptionsProperty) Get() interface{} {
+	return interface{}(rollOpts.value)
+}
+func (rollOpts *DockercliStackRollbackOptionsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.RollbackOptions); ok {
+		rollOpts.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.RollbackOptions struct")
+		return false
+	}
+}
+
+// Copy the property
+func (rollOpts *DockercliStackRollbackOptionsProperty) Copy() api_property.Property {
+	prop := &DockercliStackRollbackOptionsProperty{}
+	prop.Set(rollOpts.Get())
+	return api_property.Property(prop)
+}
+
+// Property used to pass back per service outcomes from a stack operation
+type DockercliStackServiceResultsProperty struct {
+	value handler_dockercli_stack_imported.ServiceResults
+}
+
+// Id for the property
+func (results *DockercliStackServiceResultsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_SERVICERESULTS_KEY
+}
+
+// Id for the property
+func (results *DockercliStackServiceResultsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.ServiceResults"
+}
+
+// Label for the property
+func (results *DockercliStackServiceResultsProperty) Label() string {
+	return "Docker:Stack: Service results."
+}
+
+// Description for the property
+func (results *DockercliStackServiceResultsProperty) Description() string {
+	return "Per service outcome of a docker stack command"
+}
+
+// Is the Property internal only
+func (results *DockercliStackServiceResultsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (results *DockercliStackServiceResultsProperty) Get() interface{} {
+	return interface{}(results.value)
+}
+func (results *DockercliStackServiceResultsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.ServiceResults); ok {
+		results.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.ServiceResults slice")
+		return false
+	}
+}
+
+// Copy the property
+func (results *DockercliStackServiceResultsProperty) Copy() api_property.Property {
+	prop := &DockercliStackServiceResultsProperty{}
+	prop.Set(results.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackHistoryOptionsProperty struct {
+	value handler_dockercli_stack_imported.HistoryOptions
+}
+
+// Id for the property
+func (histOpts *DockercliStackHistoryOptionsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_HISTORYOPTIONS_KEY
+}
+
+// Id for the property
+func (histOpts *DockercliStackHistoryOptionsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.HistoryOptions"
+}
+
+// Label for the property
+func (histOpts *DockercliStackHistoryOptionsProperty) Label() string {
+	return "Docker:Stack: History options."
+}
+
+// Description for the property
+func (histOpts *DockercliStackHistoryOptionsProperty) Description() string {
+	return "Deploy history options for a docker stack command"
+}
+
+// Is the Property internal only
+func (histOpts *DockercliStackHistoryOptionsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (histOpts *DockercliStackHistoryOptionsProperty) Get() interface{} {
+	return interface{}(histOpts.value)
+}
+func (histOpts *DockercliStackHistoryOptionsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.HistoryOptions); ok {
+		histOpts.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.HistoryOptions struct")
+		return false
+	}
+}
+
+// Copy the property
+func (histOpts *DockercliStackHistoryOptionsProperty) Copy() api_property.Property {
+	prop := &DockercliStackHistoryOptionsProperty{}
+	prop.Set(histOpts.Get())
+	return api_property.Property(prop)
+}
+
+// Property used to select a deploy snapshot by id, where empty means the latest
+type DockercliStackSnapshotProperty struct {
+	value string
+}
+
+// Id for the property
+func (snapshot *DockercliStackSnapshotProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_SNAPSHOT_KEY
+}
+
+// Id for the property
+func (snapshot *DockercliStackSnapshotProperty) Type() string {
+	return "string"
+}
+
+// Label for the property
+func (snapshot *DockercliStackSnapshotProperty) Label() string {
+	return "Docker:Stack: Deploy snapshot."
+}
+
+// Description for the property
+func (snapshot *DockercliStackSnapshotProperty) Description() string {
+	return "Deploy history snapshot id, leave empty for the latest snapshot"
+}
+
+// Is the Property internal only
+func (snapshot *DockercliStackSnapshotProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Optional()
+}
+
+// Property accessors
+func (snapshot *DockercliStackSnapshotProperty) Get() interface{} {
+	return interface{}(snapshot.value)
+}
+func (snapshot *DockercliStackSnapshotProperty) Set(value interface{}) bool {
+	if converted, ok := value.(string); ok {
+		snapshot.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected string")
+		return false
+	}
+}
+
+// Copy the property
+func (snapshot *DockercliStackSnapshotProperty) Copy() api_property.Property {
+	prop := &DockercliStackSnapshotProperty{}
+	prop.Set(snapshot.Get())
+	return api_property.Property(prop)
+}
+
+// Property used to select the snapshot to compare from, where empty means the previous one
+type DockercliStackSnapshotFromProperty struct {
+	value string
+}
+
+// Id for the property
+func (snapshot *DockercliStackSnapshotFromProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_SNAPSHOTFROM_KEY
+}
+
+// Id for the property
+func (snapshot *DockercliStackSnapshotFromProperty) Type() string {
+	return "string"
+}
+
+// Label for the property
+func (snapshot *DockercliStackSnapshotFromProperty) Label() string {
+	return "Docker:Stack: Compare from snapshot."
+}
+
+// Description for the property
+func (snapshot *DockercliStackSnapshotFromProperty) Description() string {
+	return "Deploy history snapshot id to compare from, leave empty for the snapshot before the compared one"
+}
+
+// Is the Property internal only
+func (snapshot *DockercliStackSnapshotFromProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Optional()
+}
+
+// Property accessors
+func (snapshot *DockercliStackSnapshotFromProperty) Get() interface{} {
+	return interface{}(snapshot.value)
+}
+func (snapshot *DockercliStackSnapshotFromProperty) Set(value interface{}) bool {
+	if converted, ok := value.(string); ok {
+		snapshot.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected string")
+		return false
+	}
+}
+
+// Copy the property
+func (snapshot *DockercliStackSnapshotFromProperty) Copy() api_property.Property {
+	prop := &DockercliStackSnapshotFromProperty{}
+	prop.Set(snapshot.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackExportOptionsProperty struct {
+	value handler_dockercli_stack_imported.ExportOptions
+}
+
+// Id for the property
+func (expOpts *DockercliStackExportOptionsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_EXPORTOPTIONS_KEY
+}
+
+// Id for the property
+func (expOpts *DockercliStackExportOptionsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.ExportOptions"
+}
+
+// Label for the property
+func (expOpts *DockercliStackExportOptionsProperty) Label() string {
+	return "Docker:Stack: Export options."
+}
+
+// Description for the property
+func (expOpts *DockercliStackExportOptionsProperty) Description() string {
+	return "Export options for a docker stack command"
+}
+
+// Is the Property internal only
+func (expOpts *DockercliStackExportOptionsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (expOpts *DockercliStackExportOptionsProperty) Get() interface{} {
+	return interface{}(expOpts.value)
+}
+func (expOpts *DockercliStackExportOptionsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.ExportOptions); ok {
+		expOpts.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.ExportOptions struct")
+		return false
+	}
+}
+
+// Copy the property
+func (expOpts *DockercliStackExportOptionsProperty) Copy() api_property.Property {
+	prop := &DockercliStackExportOptionsProperty{}
+	prop.Set(expOpts.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackVolumesOptionsProperty struct {
+	value handler_dockercli_stack_imported.VolumesOptions
+}
+
+// Id for the property
+func (volOpts *DockercliStackVolumesOptionsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_VOLUMESOPTIONS_KEY
+}
+
+// Id for the property
+func (volOpts *DockercliStackVolumesOptionsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.VolumesOptions"
+}
+
+// Label for the property
+func (volOpts *DockercliStackVolumesOptionsProperty) Label() string {
+	return "Docker:Stack: Volumes options."
+}
+
+// Description for the property
+func (volOpts *DockercliStackVolumesOptionsProperty) Description() string {
+	return "Volumes options for a docker stack command"
+}
+
+// Is the Property internal only
+func (volOpts *DockercliStackVolumesOptionsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (volOpts *DockercliStackVolumesOptionsProperty) Get() interface{} {
+	return interface{}(volOpts.value)
+}
+func (volOpts *DockercliStackVolumesOptionsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.VolumesOptions); ok {
+		volOpts.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.VolumesOptions struct")
+		return false
+	}
+}
+
+// Copy the property
+func (volOpts *DockercliStackVolumesOptionsProperty) Copy() api_property.Property {
+	prop := &DockercliStackVolumesOptionsProperty{}
+	prop.Set(volOpts.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackLogsOptionsProperty struct {
+	value handler_dockercli_stack_imported.LogsOptions
+}
+
+// Id for the property
+func (logsOpts *DockercliStackLogsOptionsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_LOGSOPTIONS_KEY
+}
+
+// Id for the property
+func (logsOpts *DockercliStackLogsOptionsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.LogsOptions"
+}
+
+// Label for the property
+func (logsOpts *DockercliStackLogsOptionsProperty) Label() string {
+	return "Docker:Stack: Logs options."
+}
+
+// Description for the property
+func (logsOpts *DockercliStackLogsOptionsProperty) Description() string {
+	return "Logs options for a docker stack command"
+}
+
+// Is the Property internal only
+func (logsOpts *DockercliStackLogsOptionsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (logsOpts *DockercliStackLogsOptionsProperty) Get() interface{} {
+	return interface{}(logsOpts.value)
+}
+func (logsOpts *DockercliStackLogsOptionsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.LogsOptions); ok {
+		logsOpts.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.LogsOptions struct")
+		return false
+	}
+}
+
+// Copy the property
+func (logsOpts *DockercliStackLogsOptionsProperty) Copy() api_property.Property {
+	prop := &DockercliStackLogsOptionsProperty{}
+	prop.Set(logsOpts.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackContextProperty struct {
+	value context.Context
+}
+
+// Id for the property
+func (ctxProp *DockercliStackContextProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_CONTEXT_KEY
+}
+
+// Id for the property
+func (ctxProp *DockercliStackContextProperty) Type() string {
+	return "context.Context"
+}
+
+// Label for the property
+func (ctxProp *DockercliStackContextProperty) Label() string {
+	return "Docker:Stack: Context."
+}
+
+// Description for the property
+func (ctxProp *DockercliStackContextProperty) Description() string {
+	return "Context which can be cancelled to stop a long running docker stack command"
+}
+
+// Is the Property internal only
+func (ctxProp *DockercliStackContextProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (ctxProp *DockercliStackContextProperty) Get() interface{} {
+	if ctxProp.value == nil {
+		return interface{}(context.Background())
+	}
+	return interface{}(ctxProp.value)
+}
+func (ctxProp *DockercliStackContextProperty) Set(value interface{}) bool {
+	if converted, ok := value.(context.Context); ok {
+		ctxProp.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected context.Context")
+		return false
+	}
+}
+
+// Copy the property
+func (ctxProp *DockercliStackContextProperty) Copy() api_property.Property {
+	prop := &DockercliStackContextProperty{}
+	prop.Set(ctxProp.Get())
+	return api_property.Property(prop)
+}
+
+// Property used to select a single stack service
+type DockercliStackServiceProperty struct {
+	value string
+}
+
+// Id for the property
+func (service *DockercliStackServiceProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_SERVICE_KEY
+}
+
+// Id for the property
+func (service *DockercliStackServiceProperty) Type() string {
+	return "string"
+}
+
+// Label for the property
+func (service *DockercliStackServiceProperty) Label() string {
+	return "Docker:Stack: Service."
+}
+
+// Description for the property
+func (service *DockercliStackServiceProperty) Description() string {
+	return "Name of a service in the stack"
+}
+
+// Is the Property internal only
+func (service *DockercliStackServiceProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Optional()
+}
+
+// Property accessors
+func (service *DockercliStackServiceProperty) Get() interface{} {
+	return interface{}(service.value)
+}
+func (service *DockercliStackServiceProperty) Set(value interface{}) bool {
+	if converted, ok := value.(string); ok {
+		service.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected string")
+		return false
+	}
+}
+
+// Copy the property
+func (service *DockercliStackServiceProperty) Copy() api_property.Property {
+	prop := &DockercliStackServiceProperty{}
+	prop.Set(service.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackCommandProperty struct {
+	value []string
+}
+
+// Id for the property
+func (command *DockercliStackCommandProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_COMMAND_KEY
+}
+
+// Id for the property
+func (command *DockercliStackCommandProperty) Type() string {
+	return "[]string"
+}
+
+// Label for the property
+func (command *DockercliStackCommandProperty) Label() string {
+	return "Docker:Stack: Command."
+}
+
+// Description for the property
+func (command *DockercliStackCommandProperty) Description() string {
+	return "Command and arguments to run"
+}
+
+// Is the Property internal only
+func (command *DockercliStackCommandProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Optional()
+}
+
+// Property accessors
+func (command *DockercliStackCommandProperty) Get() interface{} {
+	return interface{}(command.value)
+}
+func (command *DockercliStackCommandProperty) Set(value interface{}) bool {
+	if converted, ok := value.([]string); ok {
+		command.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected []string")
+		return false
+	}
+}
+
+// Copy the property
+func (command *DockercliStackCommandProperty) Copy() api_property.Property {
+	prop := &DockercliStackCommandProperty{}
+	prop.Set(command.Get())
+	return api_property.Property(prop)
+}
+
+// Property used to pass back the exit code of a command
+type DockercliStackExitCodeProperty struct {
+	value int
+}
+
+// Id for the property
+func (exitCode *DockercliStackExitCodeProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_EXITCODE_KEY
+}
+
+// Id for the property
+func (exitCode *DockercliStackExitCodeProperty) Type() string {
+	return "int"
+}
+
+// Label for the property
+func (exitCode *DockercliStackExitCodeProperty) Label() string {
+	return "Docker:Stack: Exit code."
+}
+
+// Description for the property
+func (exitCode *DockercliStackExitCodeProperty) Description() string {
+	return "Exit code of the command that was run"
+}
+
+// Is the Property internal only
+func (exitCode *DockercliStackExitCodeProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (exitCode *DockercliStackExitCodeProperty) Get() interface{} {
+	return interface{}(exitCode.value)
+}
+func (exitCode *DockercliStackExitCodeProperty) Set(value interface{}) bool {
+	if converted, ok := value.(int); ok {
+		exitCode.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected int")
+		return false
+	}
+}
+
+// Copy the property
+func (exitCode *DockercliStackExitCodeProperty) Copy() api_property.Property {
+	prop := &DockercliStackExitCodeProperty{}
+	prop.Set(exitCode.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackExecOptionsProperty struct {
+	value handler_dockercli_stack_imported.ExecOptions
+}
+
+// Id for the property
+func (execOpts *DockercliStackExecOptionsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_EXECOPTIONS_KEY
+}
+
+// Id for the property
+func (execOpts *DockercliStackExecOptionsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.ExecOptions"
+}
+
+// Label for the property
+func (execOpts *DockercliStackExecOptionsProperty) Label() string {
+	return "Docker:Stack: Exec options."
+}
+
+// Description for the property
+func (execOpts *DockercliStackExecOptionsProperty) Description() string {
+	return "Exec options for a docker stack command"
+}
+
+// Is the Property internal only
+func (execOpts *DockercliStackExecOptionsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (execOpts *DockercliStackExecOptionsProperty) Get() interface{} {
+	return interface{}(execOpts.value)
+}
+func (execOpts *DockercliStackExecOptionsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.ExecOptions); ok {
+		execOpts.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.ExecOptions struct")
+		return false
+	}
+}
+
+// Copy the property
+func (execOpts *DockercliStackExecOptionsProperty) Copy() api_property.Property {
+	prop := &DockercliStackExecOptionsProperty{}
+	prop.Set(execOpts.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackRunOptionsProperty struct {
+	value handler_dockercli_stack_imported.RunOptions
+}
+
+// Id for the property
+func (runOpts *DockercliStackRunOptionsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_RUNOPTIONS_KEY
+}
+
+// Id for the property
+func (runOpts *DockercliStackRunOptionsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.RunOptions"
+}
+
+// Label for the property
+func (runOpts *DockercliStackRunOptionsProperty) Label() string {
+	return "Docker:Stack: Run options."
+}
+
+// Description for the property
+func (runOpts *DockercliStackRunOptionsProperty) Description() string {
+	return "Run options for a docker stack command"
+}
+
+// Is the Property internal only
+func (runOpts *DockercliStackRunOptionsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (runOpts *DockercliStackRunOptionsProperty) Get() interface{} {
+	return interface{}(runOpts.value)
+}
+func (runOpts *DockercliStackRunOptionsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.RunOptions); ok {
+		runOpts.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.RunOptions struct")
+		return false
+	}
+}
+
+// Copy the property
+func (runOpts *DockercliStackRunOptionsProperty) Copy() api_property.Property {
+	prop := &DockercliStackRunOptionsProperty{}
+	prop.Set(runOpts.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackScaleOptionsProperty struct {
+	value handler_dockercli_stack_imported.ScaleOptions
+}
+
+// Id for the property
+func (scaleOpts *DockercliStackScaleOptionsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_SCALEOPTIONS_KEY
+}
+
+// Id for the property
+func (scaleOpts *DockercliStackScaleOptionsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.ScaleOptions"
+}
+
+// Label for the property
+func (scaleOpts *DockercliStackScaleOptionsProperty) Label() string {
+	return "Docker:Stack: Scale options."
+}
+
+// Description for the property
+func (scaleOpts *DockercliStackScaleOptionsProperty) Description() string {
+	return "Scale options for a docker stack command"
+}
+
+// Is the Property internal only
+func (scaleOpts *DockercliStackScaleOptionsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (scaleOpts *DockercliStackScaleOptionsProperty) Get() interface{} {
+	return interface{}(scaleOpts.value)
+}
+func (scaleOpts *DockercliStackScaleOptionsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.ScaleOptions); ok {
+		scaleOpts.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.ScaleOptions struct")
+		return false
+	}
+}
+
+// Copy the property
+func (scaleOpts *DockercliStackScaleOptionsProperty) Copy() api_property.Property {
+	prop := &DockercliStackScaleOptionsProperty{}
+	prop.Set(scaleOpts.Get())
+	return api_property.Property(prop)
+}
+
+// Property used to pass service scales, as service=replicas pairs
+type DockercliStackScalesProperty struct {
+	value []string
+}
+
+// Id for the property
+func (scales *DockercliStackScalesProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_SCALES_KEY
+}
+
+// Id for the property
+func (scales *DockercliStackScalesProperty) Type() string {
+	return "[]string"
+}
+
+// Label for the property
+func (scales *DockercliStackScalesProperty) Label() string {
+	return "Docker:Stack: Scales."
+}
+
+// Description for the property
+func (scales *DockercliStackScalesProperty) Description() string {
+	return "Replica counts for stack services, such as web=5"
+}
+
+// Is the Property internal only
+func (scales *DockercliStackScalesProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Optional()
+}
+
+// Property accessors
+func (scales *DockercliStackScalesProperty) Get() interface{} {
+	return interface{}(scales.value)
+}
+func (scales *DockercliStackScalesProperty) Set(value interface{}) bool {
+	if converted, ok := value.([]string); ok {
+		scales.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected []string")
+		return false
+	}
+}
+
+// Copy the property
+func (scales *DockercliStackScalesProperty) Copy() api_property.Property {
+	prop := &DockercliStackScalesProperty{}
+	prop.Set(scales.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackStartStopOptionsProperty struct {
+	value handler_dockercli_stack_imported.StartStopOptions
+}
+
+// Id for the property
+func (startStopOpts *DockercliStackStartStopOptionsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_STARTSTOPOPTIONS_KEY
+}
+
+// Id for the property
+func (startStopOpts *DockercliStackStartStopOptionsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.StartStopOptions"
+}
+
+// Label for the property
+func (startStopOpts *DockercliStackStartStopOptionsProperty) Label() string {
+	return "Docker:Stack: StartStop options."
+}
+
+// Description for the property
+func (startStopOpts *DockercliStackStartStopOptionsProperty) Description() string {
+	return "StartStop options for a docker stack command"
+}
+
+// Is the Property internal only
+func (startStopOpts *DockercliStackStartStopOptionsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (startStopOpts *DockercliStackStartStopOptionsProperty) Get() interface{} {
+	return interface{}(startStopOpts.value)
+}
+func (startStopOpts *DockercliStackStartStopOptionsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.StartStopOptions); ok {
+		startStopOpts.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.StartStopOptions struct")
+		return false
+	}
+}
+
+// Copy the property
+func (startStopOpts *DockercliStackStartStopOptionsProperty) Copy() api_property.Property {
+	prop := &DockercliStackStartStopOptionsProperty{}
+	prop.Set(startStopOpts.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackBuildOptionsProperty struct {
+	value handler_dockercli_stack_imported.BuildOptions
+}
+
+// Id for the property
+func (buildOpts *DockercliStackBuildOptionsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_BUILDOPTIONS_KEY
+}
+
+// Id for the property
+func (buildOpts *DockercliStackBuildOptionsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.BuildOptions"
+}
+
+// Label for the property
+func (buildOpts *DockercliStackBuildOptionsProperty) Label() string {
+	return "Docker:Stack: Build options."
+}
+
+// Description for the property
+func (buildOpts *DockercliStackBuildOptionsProperty) Description() string {
+	return "Build options for a docker stack command"
+}
+
+// Is the Property internal only
+func (buildOpts *DockercliStackBuildOptionsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (buildOpts *DockercliStackBuildOptionsProperty) Get() interface{} {
+	return interface{}(buildOpts.value)
+}
+func (buildOpts *DockercliStackBuildOptionsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.BuildOptions); ok {
+		buildOpts.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.BuildOptions struct")
+		return false
+	}
+}
+
+// Copy the property
+func (buildOpts *DockercliStackBuildOptionsProperty) Copy() api_property.Property {
+	prop := &DockercliStackBuildOptionsProperty{}
+	prop.Set(buildOpts.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackImagesOptionsProperty struct {
+	value handler_dockercli_stack_imported.ImagesOptions
+}
+
+// Id for the property
+func (imagesOpts *DockercliStackImagesOptionsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_IMAGESOPTIONS_KEY
+}
+
+// Id for the property
+func (imagesOpts *DockercliStackImagesOptionsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.ImagesOptions"
+}
+
+// Label for the property
+func (imagesOpts *DockercliStackImagesOptionsProperty) Label() string {
+	return "Docker:Stack: Images options."
+}
+
+// Description for the property
+func (imagesOpts *DockercliStackImagesOptionsProperty) Description() string {
+	return "Images options for a docker stack command"
+}
+
+// Is the Property internal only
+func (imagesOpts *DockercliStackImagesOptionsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (imagesOpts *DockercliStackImagesOptionsProperty) Get() interface{} {
+	return interface{}(imagesOpts.value)
+}
+func (imagesOpts *DockercliStackImagesOptionsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.ImagesOptions); ok {
+		imagesOpts.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.ImagesOptions struct")
+		return false
+	}
+}
+
+// Copy the property
+func (imagesOpts *DockercliStackImagesOptionsProperty) Copy() api_property.Property {
+	prop := &DockercliStackImagesOptionsProperty{}
+	prop.Set(imagesOpts.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackImageResultsProperty struct {
+	value handler_dockercli_stack_imported.ImageResults
+}
+
+// Id for the property
+func (results *DockercliStackImageResultsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_IMAGERESULTS_KEY
+}
+
+// Id for the property
+func (results *DockercliStackImageResultsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.ImageResults"
+}
+
+// Label for the property
+func (results *DockercliStackImageResultsProperty) Label() string {
+	return "Docker:Stack: Image results."
+}
+
+// Description for the property
+func (results *DockercliStackImageResultsProperty) Description() string {
+	return "Per image outcome of a docker stack image command"
+}
+
+// Is the Property internal only
+func (results *DockercliStackImageResultsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (results *DockercliStackImageResultsProperty) Get() interface{} {
+	return interface{}(results.value)
+}
+func (results *DockercliStackImageResultsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.ImageResults); ok {
+		results.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.ImageResults slice")
+		return false
+	}
+}
+
+// Copy the property
+func (results *DockercliStackImageResultsProperty) Copy() api_property.Property {
+	prop := &DockercliStackImageResultsProperty{}
+	prop.Set(results.Get())
+	return api_property.Property(prop)
+}
+
+// Property holding a handler for image progress events, called concurrently
+type DockercliStackImageEventsProperty struct {
+	value func(handler_dockercli_stack_imported.ImageEvent)
+}
+
+// Id for the property
+func (events *DockercliStackImageEventsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_IMAGEEVENTS_KEY
+}
+
+// Id for the property
+func (events *DockercliStackImageEventsProperty) Type() string {
+	return "func(github.com/wunderkraut/radi-handler-dockercli/stack/stack.ImageEvent)"
+}
+
+// Label for the property
+func (events *DockercliStackImageEventsProperty) Label() string {
+	return "Docker:Stack: Image events."
+}
+
+// Description for the property
+func (events *DockercliStackImageEventsProperty) Description() string {
+	return "Handler receiving per image progress events of a docker stack image command, instead of the cli output.  It is called concurrently for images transferred in parallel."
+}
+
+// Is the Property internal only
+func (events *DockercliStackImageEventsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (events *DockercliStackImageEventsProperty) Get() interface{} {
+	return interface{}(events.value)
+}
+func (events *DockercliStackImageEventsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(func(handler_dockercli_stack_imported.ImageEvent)); ok {
+		events.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected func(github.com/wunderkraut/radi-handler-dockercli/stack/stack.ImageEvent)")
+		return false
+	}
+}
+
+// Copy the property
+func (events *DockercliStackImageEventsProperty) Copy() api_property.Property {
+	prop := &DockercliStackImageEventsProperty{}
+	prop.Set(events.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackWatchOptionsProperty struct {
+	value handler_dockercli_stack_imported.WatchOptions
+}
+
+// Id for the property
+func (watchOpts *DockercliStackWatchOptionsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_WATCHOPTIONS_KEY
+}
+
+// Id for the property
+func (watchOpts *DockercliStackWatchOptionsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.WatchOptions"
+}
+
+// Label for the property
+func (watchOpts *DockercliStackWatchOptionsProperty) Label() string {
+	return "Docker:Stack: Watch options."
+}
+
+// Description for the property
+func (watchOpts *DockercliStackWatchOptionsProperty) Description() string {
+	return "Watch options for a docker stack command"
+}
+
+// Is the Property internal only
+func (watchOpts *DockercliStackWatchOptionsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (watchOpts *DockercliStackWatchOptionsProperty) Get() interface{} {
+	return interface{}(watchOpts.value)
+}
+func (watchOpts *DockercliStackWatchOptionsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.WatchOptions); ok {
+		watchOpts.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.WatchOptions struct")
+		return false
+	}
+}
+
+// Copy the property
+func (watchOpts *DockercliStackWatchOptionsProperty) Copy() api_property.Property {
+	prop := &DockercliStackWatchOptionsProperty{}
+	prop.Set(watchOpts.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackEventsOptionsProperty struct {
+	value handler_dockercli_stack_imported.EventsOptions
+}
+
+// Id for the property
+func (eventsOpts *DockercliStackEventsOptionsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_EVENTSOPTIONS_KEY
+}
+
+// Id for the property
+func (eventsOpts *DockercliStackEventsOptionsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.EventsOptions"
+}
+
+// Label for the property
+func (eventsOpts *DockercliStackEventsOptionsProperty) Label() string {
+	return "Docker:Stack: Events options."
+}
+
+// Description for the property
+func (eventsOpts *DockercliStackEventsOptionsProperty) Description() string {
+	return "Events options for a docker stack command"
+}
+
+// Is the Property internal only
+func (eventsOpts *DockercliStackEventsOptionsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (eventsOpts *DockercliStackEventsOptionsProperty) Get() interface{} {
+	return interface{}(eventsOpts.value)
+}
+func (eventsOpts *DockercliStackEventsOptionsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.EventsOptions); ok {
+		eventsOpts.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.EventsOptions struct")
+		return false
+	}
+}
+
+// Copy the property
+func (eventsOpts *DockercliStackEventsOptionsProperty) Copy() api_property.Property {
+	prop := &DockercliStackEventsOptionsProperty{}
+	prop.Set(eventsOpts.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackStatusProperty struct {
+	value *handler_dockercli_stack_imported.StackStatus
+}
+
+// Id for the property
+func (status *DockercliStackStatusProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_STATUS_KEY
+}
+
+// Id for the property
+func (status *DockercliStackStatusProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.StackStatus"
+}
+
+// Label for the property
+func (status *DockercliStackStatusProperty) Label() string {
+	return "Docker:Stack: Status."
+}
+
+// Description for the property
+func (status *DockercliStackStatusProperty) Description() string {
+	return "Per service status and overall verdict of a docker stack"
+}
+
+// Is the Property internal only
+func (status *DockercliStackStatusProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (status *DockercliStackStatusProperty) Get() interface{} {
+	return interface{}(status.value)
+}
+func (status *DockercliStackStatusProperty) Set(value interface{}) bool {
+	if converted, ok := value.(*handler_dockercli_stack_imported.StackStatus); ok {
+		status.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.StackStatus pointer")
+		return false
+	}
+}
+
+// Copy the property
+func (status *DockercliStackStatusProperty) Copy() api_property.Property {
+	prop := &DockercliStackStatusProperty{}
+	prop.Set(status.Get())
+	return api_property.Property(prop)
+}
+
+type DockercliStackStatusOptionsProperty struct {
+	value handler_dockercli_stack_imported.StatusOptions
+}
+
+// Id for the property
+func (statusOpts *DockercliStackStatusOptionsProperty) Id() string {
+	return OPERATION_PROPERTY_DOCKER_STACK_STATUSOPTIONS_KEY
+}
+
+// Id for the property
+func (statusOpts *DockercliStackStatusOptionsProperty) Type() string {
+	return "github.com/wunderkraut/radi-handler-dockercli/stack/stack.StatusOptions"
+}
+
+// Label for the property
+func (statusOpts *DockercliStackStatusOptionsProperty) Label() string {
+	return "Docker:Stack: Status options."
+}
+
+// Description for the property
+func (statusOpts *DockercliStackStatusOptionsProperty) Description() string {
+	return "Status options for a docker stack command"
+}
+
+// Is the Property internal only
+func (statusOpts *DockercliStackStatusOptionsProperty) Usage() api_usage.Usage {
+	return api_property.Usage_Internal()
+}
+
+// Property accessors
+func (statusOpts *DockercliStackStatusOptionsProperty) Get() interface{} {
+	return interface{}(statusOpts.value)
+}
+func (statusOpts *DockercliStackStatusOptionsProperty) Set(value interface{}) bool {
+	if converted, ok := value.(handler_dockercli_stack_imported.StatusOptions); ok {
+		statusOpts.value = converted
+		return true
+	} else {
+		log.WithFields(log.Fields{"value": value}).Error("Could not assign Property value, because the passed parameter was the wrong type. Expected github.com/wunderkraut/radi-handler-dockercli/stack/stack.StatusOptions struct")
+		return false
+	}
+}
+
+// Copy the property
+func (statusOpts *DockercliStackStatusOptionsProperty) Copy() api_property.Property {
+	prop := &DockercliStackStatusOptionsProperty{}
+	prop.Set(statusOpts.Get())
+	return api_property.Property(prop)
+}
@@ -0,0 +1,106 @@
+package stack
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_ROLLBACK = "dockercli.stack.orchestrate.rollback"
+)
+
+/**
+ * Rollback operation
+ */
+
+// Operation that reverts all stack services to their previous spec
+type DockercliStackRollbackOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (rollback *DockercliStackRollbackOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_ROLLBACK
+}
+
+// Label the operation
+func (rollback *DockercliStackRollbackOperation) Label() string {
+	return "Rollback"
+}
+
+// Description for the operation
+func (rollback *DockercliStackRollbackOperation) Description() string {
+	return "Roll back all stack services to their previously deployed spec."
+}
+
+// Man page for the operation
+func (rollback *DockercliStackRollbackOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (rollback *DockercliStackRollbackOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (rollback *DockercliStackRollbackOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use a rollback Opts property, with a default set to the configured RollbackOptions
+	props.Add(api_property.Property(rollback.RollbackOptionsProperty()))
+	// Per service outcomes are passed back in this property
+	props.Add(api_property.Property(&DockercliStackServiceResultsProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (rollback *DockercliStackRollbackOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (rollback *DockercliStackRollbackOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_ROLLBACKOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.RollbackOptions)
+
+		cli := rollback.DockerCli()
+
+		log.WithFields(log.Fields{"RollbackOptions": opts}).Info("Running Rollback using docker cli stack")
+
+		results, err := handler_dockercli_stack_imported.RunRollback(cli, opts)
+
+		for _, result := range results {
+			if result.Error == nil {
+				log.WithFields(log.Fields{"service": result.Name, "id": result.ID}).Info("Rolled back service")
+			} else {
+				log.WithError(result.Error).WithFields(log.Fields{"service": result.Name, "id": result.ID}).Error("Failed to roll back service")
+			}
+		}
+		if resultsProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_SERVICERESULTS_KEY); found {
+			resultsProp.Set(results)
+		}
+
+		if err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
@@ -0,0 +1,126 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_RUN = "dockercli.stack.command.run"
+)
+
+/**
+ * Command operations
+ */
+
+// Operation that runs a one-off job, copied from a stack service
+type DockercliStackRunOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (run *DockercliStackRunOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_RUN
+}
+
+// Label the operation
+func (run *DockercliStackRunOperation) Label() string {
+	return "Run"
+}
+
+// Description for the operation
+func (run *DockercliStackRunOperation) Description() string {
+	return "Run a one-off job with the image, environment, secrets and networks of a stack service."
+}
+
+// Man page for the operation
+func (run *DockercliStackRunOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (run *DockercliStackRunOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (run *DockercliStackRunOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use a run Opts property, with a default set to the configured RunOptions
+	props.Add(api_property.Property(run.RunOptionsProperty()))
+	// The service to copy, and optionally the command to run instead of its own
+	props.Add(api_property.Property(&DockercliStackServiceProperty{}))
+	props.Add(api_property.Property(&DockercliStackCommandProperty{}))
+	// The exit code of the job is passed back in this property
+	props.Add(api_property.Property(&DockercliStackExitCodeProperty{}))
+	// Cancelling this context stops waiting for the job, and removes it
+	props.Add(api_property.Property(&DockercliStackContextProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (run *DockercliStackRunOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (run *DockercliStackRunOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_RUNOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.RunOptions)
+
+		if serviceProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_SERVICE_KEY); found {
+			if service := serviceProp.Get().(string); service != "" {
+				opts.SetService(service)
+			}
+		}
+		if commandProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_COMMAND_KEY); found {
+			if command := commandProp.Get().([]string); len(command) > 0 {
+				opts.SetCommand(command)
+			}
+		}
+
+		ctx := context.Background()
+		if ctxProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_CONTEXT_KEY); found {
+			ctx = ctxProp.Get().(context.Context)
+		}
+
+		cli := run.DockerCli()
+
+		log.WithFields(log.Fields{"RunOptions": opts}).Info("Running Run using docker cli stack")
+
+		exitCode, err := handler_dockercli_stack_imported.RunJob(ctx, cli, opts)
+		if err == nil && exitCode != 0 {
+			err = fmt.Errorf("Job exited with status %d", exitCode)
+		}
+
+		if exitCodeProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_EXITCODE_KEY); found {
+			exitCodeProp.Set(exitCode)
+		}
+
+		if err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
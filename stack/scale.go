@@ -0,0 +1,121 @@
+package stack
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_SCALE = "dockercli.stack.orchestrate.scale"
+)
+
+/**
+ * Scale operation
+ */
+
+// Operation that changes the replica counts of stack services
+type DockercliStackScaleOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (scale *DockercliStackScaleOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_SCALE
+}
+
+// Label the operation
+func (scale *DockercliStackScaleOperation) Label() string {
+	return "Scale"
+}
+
+// Description for the operation
+func (scale *DockercliStackScaleOperation) Description() string {
+	return "Change the number of replicas of stack services, such as web=5."
+}
+
+// Man page for the operation
+func (scale *DockercliStackScaleOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (scale *DockercliStackScaleOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (scale *DockercliStackScaleOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use a scale Opts property, with a default set to the configured ScaleOptions
+	props.Add(api_property.Property(scale.ScaleOptionsProperty()))
+	// The services to scale, as service=replicas pairs
+	props.Add(api_property.Property(&DockercliStackScalesProperty{}))
+	// Per service outcomes are passed back in this property
+	props.Add(api_property.Property(&DockercliStackServiceResultsProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (scale *DockercliStackScaleOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (scale *DockercliStackScaleOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_SCALEOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.ScaleOptions)
+
+		if scalesProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_SCALES_KEY); found {
+			if args := scalesProp.Get().([]string); len(args) > 0 {
+				scales, err := handler_dockercli_stack_imported.ParseScales(args)
+				if err != nil {
+					res.AddError(err)
+					res.MarkFailed()
+					res.MarkFinished()
+					return
+				}
+				opts.SetScales(scales)
+			}
+		}
+
+		cli := scale.DockerCli()
+
+		log.WithFields(log.Fields{"ScaleOptions": opts}).Info("Running Scale using docker cli stack")
+
+		results, err := handler_dockercli_stack_imported.RunScale(cli, opts)
+
+		for _, result := range results {
+			if result.Error == nil {
+				log.WithFields(log.Fields{"service": result.Name, "id": result.ID}).Info("Scaled service")
+			} else {
+				log.WithError(result.Error).WithFields(log.Fields{"service": result.Name, "id": result.ID}).Error("Failed to scale service")
+			}
+		}
+		if resultsProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_SERVICERESULTS_KEY); found {
+			resultsProp.Set(results)
+		}
+
+		if err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
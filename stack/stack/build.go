@@ -0,0 +1,315 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/builder/dockerignore"
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/cli/compose/convert"
+	composetypes "github.com/docker/docker/cli/compose/types"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+/**
+ * Image builds
+ *
+ * The compose loader does not support `build:` sections, so they are taken
+ * out of the parsed compose file before it is loaded.  Services that build
+ * their image, but do not name it, get a stack scoped image name, which is
+ * also used when the service is deployed.
+ *
+ * A stack scoped image only exists on the engine that built it, so swarm
+ * deploys need an explicit image name, and push the images that they build
+ * so that every node can pull them.
+ */
+
+type BuildOptions struct {
+	composefile string
+	namespace   string
+	pull        bool
+	noCache     bool
+}
+
+func New_BuildOptions(composefile string, namespace string) *BuildOptions {
+	return &BuildOptions{
+		composefile: composefile,
+		namespace:   namespace,
+	}
+}
+
+// Always pull newer versions of the base images
+func (opts *BuildOptions) SetPull(pull bool) {
+	opts.pull = pull
+}
+
+// Do not use the build cache
+func (opts *BuildOptions) SetNoCache(noCache bool) {
+	opts.noCache = noCache
+}
+
+// composeBuild is the build section of a compose service
+type composeBuild struct {
+	Context    string
+	Dockerfile string
+	Args       map[string]*string
+	// Image is the name that the built image is tagged with
+	Image string
+	// Scoped is set when the image name was made up from the stack namespace
+	Scoped bool
+}
+
+// RunBuild builds the images of all stack services that have a build section
+func RunBuild(dockerCli *command.DockerCli, opts BuildOptions) (ServiceResults, error) {
+	ctx := context.Background()
+
+	configDetails, err := getConfigDetails(DeployOptions{composefile: opts.composefile})
+	if err != nil {
+		return nil, err
+	}
+
+	builds, err := extractComposeBuilds(&configDetails, convert.NewNamespace(opts.namespace))
+	if err != nil {
+		return nil, err
+	}
+	if len(builds) == 0 {
+		fmt.Fprintf(dockerCli.Out(), "No services to build in: %s\n", opts.composefile)
+		return ServiceResults{}, nil
+	}
+
+	results := buildImages(ctx, dockerCli, builds, opts)
+	return results, results.Err()
+}
+
+// deployBuilds takes the build sections out of the compose file before it is
+// loaded for a deploy, and builds the images first if the deploy asks for it
+func deployBuilds(ctx context.Context, dockerCli *command.DockerCli, details *composetypes.ConfigDetails, opts DeployOptions) error {
+	builds, err := extractComposeBuilds(details, convert.NewNamespace(opts.namespace))
+	if err != nil {
+		return err
+	}
+	if len(builds) == 0 {
+		return nil
+	}
+
+	if opts.mode != DeployModeLocal {
+		var unnamed []string
+		for name, build := range builds {
+			if build.Scoped {
+				unnamed = append(unnamed, name)
+			}
+		}
+		if len(unnamed) > 0 {
+			sort.Strings(unnamed)
+			return fmt.Errorf("Services built for a swarm need an image name to push to, add an image to: %s", strings.Join(unnamed, ", "))
+		}
+	}
+
+	if !opts.build {
+		return nil
+	}
+
+	buildOpts := New_BuildOptions(opts.composefile, opts.namespace)
+	if err := buildImages(ctx, dockerCli, builds, *buildOpts).Err(); err != nil {
+		return err
+	}
+	if opts.mode == DeployModeLocal {
+		return nil
+	}
+	return pushBuiltImages(ctx, dockerCli, builds, opts)
+}
+
+// pushBuiltImages pushes the built images to their registries, so that all
+// swarm nodes can pull them
+func pushBuiltImages(ctx context.Context, dockerCli *command.DockerCli, builds map[string]composeBuild, opts DeployOptions) error {
+	resolver := opts.registryAuth
+	if resolver == nil {
+		resolver = New_CliRegistryAuthResolver()
+	}
+	push := func(ctx context.Context, image, encodedAuth string) (io.ReadCloser, error) {
+		return dockerCli.Client().ImagePush(ctx, image, types.ImagePushOptions{RegistryAuth: encodedAuth})
+	}
+
+	images := map[string]struct{}{}
+	for _, build := range builds {
+		images[build.Image] = struct{}{}
+	}
+	var sorted []string
+	for image := range images {
+		sorted = append(sorted, image)
+	}
+	sort.Strings(sorted)
+
+	for _, image := range sorted {
+		fmt.Fprintf(dockerCli.Out(), "Pushing %s\n", image)
+		if err := transferImage(ctx, dockerCli, image, "push", resolver, push, printImageEvent(dockerCli)); err != nil {
+			return fmt.Errorf("Failed to push %s: %s", image, err)
+		}
+	}
+	return nil
+}
+
+// extractComposeBuilds removes the build sections from the compose services,
+// returning them keyed by service name.  Services without an image are given
+// a stack scoped image name.  Relative build contexts are relative to the
+// compose working directory.
+func extractComposeBuilds(details *composetypes.ConfigDetails, namespace convert.Namespace) (map[string]composeBuild, error) {
+	builds := map[string]composeBuild{}
+
+	for _, configFile := range details.ConfigFiles {
+		services, _ := configFile.Config["services"].(map[string]interface{})
+		for serviceName, rawService := range services {
+			service, ok := rawService.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			raw, exists := service["build"]
+			if !exists {
+				continue
+			}
+			delete(service, "build")
+
+			build, err := parseComposeBuild(serviceName, raw)
+			if err != nil {
+				return nil, err
+			}
+			if !filepath.IsAbs(build.Context) {
+				build.Context = filepath.Join(details.WorkingDir, build.Context)
+			}
+
+			build.Image, _ = service["image"].(string)
+			if build.Image == "" {
+				build.Image = namespace.Scope(serviceName) + ":latest"
+				build.Scoped = true
+				service["image"] = build.Image
+			}
+			builds[serviceName] = build
+		}
+	}
+
+	return builds, nil
+}
+
+func parseComposeBuild(serviceName string, raw interface{}) (composeBuild, error) {
+	build := composeBuild{}
+
+	switch values := raw.(type) {
+	case string:
+		build.Context = values
+	case map[string]interface{}:
+		build.Context, _ = values["context"].(string)
+		build.Dockerfile, _ = values["dockerfile"].(string)
+
+		switch args := values["args"].(type) {
+		case map[string]interface{}:
+			build.Args = map[string]*string{}
+			for key, value := range args {
+				if value == nil {
+					build.Args[key] = nil
+					continue
+				}
+				arg := fmt.Sprint(value)
+				build.Args[key] = &arg
+			}
+		case []interface{}:
+			build.Args = map[string]*string{}
+			for _, value := range args {
+				parts := strings.SplitN(fmt.Sprint(value), "=", 2)
+				if len(parts) == 1 {
+					// take the value from the environment, as compose does
+					if env, exists := os.LookupEnv(parts[0]); exists {
+						build.Args[parts[0]] = &env
+					} else {
+						build.Args[parts[0]] = nil
+					}
+					continue
+				}
+				build.Args[parts[0]] = &parts[1]
+			}
+		}
+	default:
+		return build, fmt.Errorf("build for service %s must be a string or a mapping", serviceName)
+	}
+
+	if build.Context == "" {
+		build.Context = "."
+	}
+	return build, nil
+}
+
+// buildImages builds the images one at a time, in service name order, as
+// services often share base images
+func buildImages(ctx context.Context, dockerCli *command.DockerCli, builds map[string]composeBuild, opts BuildOptions) ServiceResults {
+	var names []string
+	for name := range builds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := ServiceResults{}
+	for _, name := range names {
+		build := builds[name]
+		result := ServiceResult{Name: name, Action: "build"}
+
+		fmt.Fprintf(dockerCli.Out(), "Building %s as %s\n", name, build.Image)
+		if result.Error = buildImage(ctx, dockerCli, build, opts); result.Error != nil {
+			fmt.Fprintf(dockerCli.Err(), "Failed to build %s: %s\n", name, result.Error)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// buildImage sends the build context to the engine, and streams the output
+func buildImage(ctx context.Context, dockerCli *command.DockerCli, build composeBuild, opts BuildOptions) error {
+	excludes, err := readDockerignore(build.Context)
+	if err != nil {
+		return err
+	}
+
+	buildContext, err := archive.TarWithOptions(build.Context, &archive.TarOptions{
+		ExcludePatterns: excludes,
+	})
+	if err != nil {
+		return err
+	}
+	defer buildContext.Close()
+
+	authConfigs, _ := dockerCli.GetAllCredentials()
+
+	response, err := dockerCli.Client().ImageBuild(ctx, buildContext, types.ImageBuildOptions{
+		Tags:        []string{build.Image},
+		Dockerfile:  build.Dockerfile,
+		BuildArgs:   build.Args,
+		Remove:      true,
+		PullParent:  opts.pull,
+		NoCache:     opts.noCache,
+		AuthConfigs: authConfigs,
+	})
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	return jsonmessage.DisplayJSONMessagesToStream(response.Body, dockerCli.Out(), nil)
+}
+
+// readDockerignore reads the exclude patterns of a build context
+func readDockerignore(contextDir string) ([]string, error) {
+	file, err := os.Open(filepath.Join(contextDir, ".dockerignore"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return dockerignore.ReadAll(file)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/cli/compose/convert"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/opts"
@@ -58,3 +59,35 @@ func getStackSecrets(
 		ctx,
 		types.SecretListOptions{Filters: getStackFilter(namespace)})
 }
+
+// Swarm configs need this API version on both the client and the daemon
+const configsAPIVersion = "1.30"
+
+// configsSupported checks whether both the client and the daemon know about
+// swarm configs, so that older daemons can still deploy stacks without them
+func configsSupported(ctx context.Context, apiclient client.APIClient) (bool, error) {
+	if versions.LessThan(apiclient.ClientVersion(), configsAPIVersion) {
+		return false, nil
+	}
+
+	serverVersion, err := apiclient.ServerVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+	return !versions.LessThan(serverVersion.APIVersion, configsAPIVersion), nil
+}
+
+// getStackConfigs lists the stack configs, of which there are none if the
+// daemon does not support configs
+func getStackConfigs(
+	ctx context.Context,
+	apiclient client.APIClient,
+	namespace string,
+) ([]swarm.Config, error) {
+	if supported, err := configsSupported(ctx, apiclient); err != nil || !supported {
+		return nil, err
+	}
+	return apiclient.ConfigList(
+		ctx,
+		types.ConfigListOptions{Filters: getStackFilter(namespace)})
+}
@@ -0,0 +1,87 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+)
+
+const (
+	// By default wait this long for a stack to converge
+	defaultConvergeTimeout = 5 * time.Minute
+	// How often to check if a stack has converged
+	convergePollInterval = time.Second
+)
+
+// waitForConvergence polls the stack services and tasks until every service
+// has finished updating and runs only the tasks it wants, or the timeout
+// passes.
+func waitForConvergence(ctx context.Context, apiclient client.APIClient, namespace string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		converged, err := stackConverged(ctx, apiclient, namespace)
+		if err != nil {
+			return err
+		}
+		if converged {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Stack %s did not converge within %s", namespace, timeout)
+		case <-time.After(convergePollInterval):
+		}
+	}
+}
+
+// stackConverged tells if all services in the stack are converged
+func stackConverged(ctx context.Context, apiclient client.APIClient, namespace string) (bool, error) {
+	services, err := getServices(ctx, apiclient, namespace)
+	if err != nil {
+		return false, err
+	}
+	if len(services) == 0 {
+		return true, nil
+	}
+
+	taskFilter := filters.NewArgs()
+	for _, service := range services {
+		if service.UpdateStatus != nil && service.UpdateStatus.State == swarm.UpdateStateUpdating {
+			return false, nil
+		}
+		taskFilter.Add("service", service.ID)
+	}
+
+	tasks, err := apiclient.TaskList(ctx, types.TaskListOptions{Filters: taskFilter})
+	if err != nil {
+		return false, err
+	}
+
+	running := map[string]uint64{}
+	for _, task := range tasks {
+		switch {
+		case task.DesiredState == swarm.TaskStateRunning && task.Status.State == swarm.TaskStateRunning:
+			running[task.ServiceID]++
+		case task.DesiredState != swarm.TaskStateRunning && task.Status.State == swarm.TaskStateRunning:
+			// an old task is still shutting down
+			return false, nil
+		}
+	}
+
+	for _, service := range services {
+		if service.Spec.Mode.Replicated != nil && service.Spec.Mode.Replicated.Replicas != nil {
+			if running[service.ID] != *service.Spec.Mode.Replicated.Replicas {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
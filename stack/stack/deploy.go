@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/docker/docker/cli/command"
 )
 
 const (
 	defaultNetworkDriver = "overlay"
+	// By default services are deployed one at a time, as docker does
+	defaultDeployParallelism = 1
 )
 
 type DeployOptions struct {
@@ -17,6 +20,27 @@ type DeployOptions struct {
 	composefile      string
 	namespace        string
 	sendRegistryAuth bool
+
+	// historyPath is where deploy snapshots are recorded, empty disables history
+	historyPath string
+	// parallelism is how many services are created or updated at once
+	parallelism int
+	// updateRetries is how often a conflicting service update is retried
+	updateRetries int
+	// resolveImage pins service images to their registry digest
+	resolveImage bool
+	// registryAuth provides per image registry auth, replacing sendRegistryAuth
+	registryAuth RegistryAuthResolver
+	// versionSecrets gives each secret version its own content hashed name
+	versionSecrets bool
+	// convergeTimeout is how long to wait for services to converge
+	convergeTimeout time.Duration
+	// mode is DeployModeSwarm, or DeployModeLocal to run plain containers
+	mode string
+	// build the images of services with a build section before deploying
+	build bool
+	// monitorRollout waits for the rollouts of updated services to finish
+	monitorRollout bool
 }
 
 func New_DeployOptions(bundlefile string, composefile string, namespace string, sendRegistryAuth bool) *DeployOptions {
@@ -25,22 +49,102 @@ func New_DeployOptions(bundlefile string, composefile string, namespace string,
 		composefile:      composefile,
 		namespace:        namespace,
 		sendRegistryAuth: sendRegistryAuth,
+		parallelism:      defaultDeployParallelism,
+		updateRetries:    defaultUpdateRetries,
+		convergeTimeout:  defaultConvergeTimeout,
+		mode:             DeployModeSwarm,
 	}
 }
 
-func RunDeploy(dockerCli *command.DockerCli, opts DeployOptions) error {
+// Record a snapshot of every successful deploy in this path
+func (opts *DeployOptions) SetHistoryPath(path string) {
+	opts.historyPath = path
+}
+
+// Create or update this many services at the same time
+func (opts *DeployOptions) SetParallelism(parallelism int) {
+	opts.parallelism = parallelism
+}
+
+// Retry service updates this many times if the service was changed by someone else
+func (opts *DeployOptions) SetUpdateRetries(retries int) {
+	opts.updateRetries = retries
+}
+
+// Pin service images to the digest that their tag currently points to
+func (opts *DeployOptions) SetResolveImage(resolveImage bool) {
+	opts.resolveImage = resolveImage
+}
+
+// Use a resolver to provide the registry auth for each service image
+func (opts *DeployOptions) SetRegistryAuthResolver(resolver RegistryAuthResolver) {
+	opts.registryAuth = resolver
+}
+
+// Name secrets by a hash of their content, so that changed secrets are rotated
+func (opts *DeployOptions) SetVersionSecrets(versionSecrets bool) {
+	opts.versionSecrets = versionSecrets
+}
+
+// Wait this long for services to converge, when a deploy needs to wait
+func (opts *DeployOptions) SetConvergeTimeout(timeout time.Duration) {
+	opts.convergeTimeout = timeout
+}
+
+// Deploy to a swarm, or as plain containers to a single engine
+func (opts *DeployOptions) SetMode(mode string) {
+	opts.mode = mode
+}
+
+// Build the images of services with a build section before deploying
+func (opts *DeployOptions) SetBuild(build bool) {
+	opts.build = build
+}
+
+// Wait for updated services to finish rolling out, and fail if an update pauses or rolls back
+func (opts *DeployOptions) SetMonitorRollout(monitorRollout bool) {
+	opts.monitorRollout = monitorRollout
+}
+
+// DeployResult reports what a deploy did
+type DeployResult struct {
+	// Services holds the outcome for each service, sorted by name
+	Services ServiceResults
+	// Images maps service images to the digest they were pinned to, if
+	// image resolution was enabled
+	Images map[string]string
+
+	snapshot *Snapshot
+}
+
+// RunDeploy deploys the stack.  A result is returned even if some services
+// failed, so that the outcome of each service can be reported.
+func RunDeploy(dockerCli *command.DockerCli, opts DeployOptions) (*DeployResult, error) {
 	ctx := context.Background()
 
+	var result *DeployResult
+	var err error
+
 	switch {
+	case opts.mode != DeployModeSwarm && opts.mode != DeployModeLocal:
+		return nil, fmt.Errorf("Unknown deploy mode %q, expected %q or %q", opts.mode, DeployModeSwarm, DeployModeLocal)
 	case opts.bundlefile == "" && opts.composefile == "":
-		return fmt.Errorf("Please specify either a bundle file (with --bundle-file) or a Compose file (with --compose-file).")
+		return nil, fmt.Errorf("Please specify either a bundle file (with --bundle-file) or a Compose file (with --compose-file).")
 	case opts.bundlefile != "" && opts.composefile != "":
-		return fmt.Errorf("You cannot specify both a bundle file and a Compose file.")
+		return nil, fmt.Errorf("You cannot specify both a bundle file and a Compose file.")
+	case opts.mode == DeployModeLocal:
+		result, err = deployLocal(ctx, dockerCli, opts)
 	case opts.bundlefile != "":
-		return deployBundle(ctx, dockerCli, opts)
+		result, err = deployBundle(ctx, dockerCli, opts)
 	default:
-		return deployCompose(ctx, dockerCli, opts)
+		result, err = deployCompose(ctx, dockerCli, opts)
 	}
+	if err != nil {
+		return result, err
+	}
+
+	recordSnapshot(ctx, dockerCli, opts.historyPath, result.snapshot)
+	return result, nil
 }
 
 // checkDaemonIsSwarmManager does an Info API call to verify that the daemon is
@@ -9,14 +9,14 @@ import (
 	"github.com/docker/docker/cli/compose/convert"
 )
 
-func deployBundle(ctx context.Context, dockerCli *command.DockerCli, opts DeployOptions) error {
+func deployBundle(ctx context.Context, dockerCli *command.DockerCli, opts DeployOptions) (*DeployResult, error) {
 	bundle, err := loadBundlefile(dockerCli.Err(), opts.namespace, opts.bundlefile)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := checkDaemonIsSwarmManager(ctx, dockerCli); err != nil {
-		return err
+		return nil, err
 	}
 
 	namespace := convert.NewNamespace(opts.namespace)
@@ -77,7 +77,22 @@ func deployBundle(ctx context.Context, dockerCli *command.DockerCli, opts Deploy
 	}
 
 	if err := createNetworks(ctx, dockerCli, namespace, networks); err != nil {
-		return err
+		return nil, err
 	}
-	return deployServices(ctx, dockerCli, services, namespace, opts.sendRegistryAuth)
+	images := map[string]string{}
+	if opts.resolveImage {
+		if images, err = pinImageDigests(ctx, dockerCli, services, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	results, err := deployServices(ctx, dockerCli, services, namespace, opts)
+	if err != nil {
+		return &DeployResult{Services: results, Images: images}, err
+	}
+	return &DeployResult{
+		Services: results,
+		Images:   images,
+		snapshot: newSnapshot(opts, bundle, networks, services),
+	}, nil
 }
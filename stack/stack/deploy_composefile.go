@@ -1,10 +1,11 @@
 package stack
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io/ioutil"
-	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -18,20 +19,29 @@ import (
 	dockerclient "github.com/docker/docker/client"
 )
 
-func deployCompose(ctx context.Context, dockerCli *command.DockerCli, opts DeployOptions) error {
+func deployCompose(ctx context.Context, dockerCli *command.DockerCli, opts DeployOptions) (*DeployResult, error) {
 	configDetails, err := getConfigDetails(opts)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// configs are not known to the loader, so they are handled separately
+	composeConfigs, composeServiceConfigs, err := extractComposeConfigs(&configDetails)
+	if err != nil {
+		return nil, err
+	}
+	if err := deployBuilds(ctx, dockerCli, &configDetails, opts); err != nil {
+		return nil, err
 	}
 
 	config, err := loader.Load(configDetails)
 	if err != nil {
 		if fpe, ok := err.(*loader.ForbiddenPropertiesError); ok {
-			return fmt.Errorf("Compose file contains unsupported options:\n\n%s\n",
+			return nil, fmt.Errorf("Compose file contains unsupported options:\n\n%s\n",
 				propertyWarnings(fpe.Properties))
 		}
 
-		return err
+		return nil, err
 	}
 
 	unsupportedProperties := loader.GetUnsupportedProperties(configDetails)
@@ -47,7 +57,7 @@ func deployCompose(ctx context.Context, dockerCli *command.DockerCli, opts Deplo
 	}
 
 	if err := checkDaemonIsSwarmManager(ctx, dockerCli); err != nil {
-		return err
+		return nil, err
 	}
 
 	namespace := convert.NewNamespace(opts.namespace)
@@ -56,25 +66,75 @@ func deployCompose(ctx context.Context, dockerCli *command.DockerCli, opts Deplo
 
 	networks, externalNetworks := convert.Networks(namespace, config.Networks, serviceNetworks)
 	if err := validateExternalNetworks(ctx, dockerCli, externalNetworks); err != nil {
-		return err
+		return nil, err
 	}
 	if err := createNetworks(ctx, dockerCli, namespace, networks); err != nil {
-		return err
+		return nil, err
 	}
 
 	secrets, err := convert.Secrets(namespace, config.Secrets)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if opts.versionSecrets {
+		secrets = versionSecrets(namespace, config, secrets)
 	}
 	if err := createSecrets(ctx, dockerCli, namespace, secrets); err != nil {
-		return err
+		return nil, err
+	}
+
+	if len(composeConfigs) > 0 {
+		supported, err := configsSupported(ctx, dockerCli.Client())
+		if err != nil {
+			return nil, err
+		}
+		if !supported {
+			return nil, fmt.Errorf("Compose file declares configs, which need docker API %s or later", configsAPIVersion)
+		}
+	}
+	configs, err := convertConfigs(namespace, configDetails.WorkingDir, composeConfigs)
+	if err != nil {
+		return nil, err
+	}
+	if err := createConfigs(ctx, dockerCli, namespace, configs); err != nil {
+		return nil, err
 	}
 
 	services, err := convert.Services(namespace, config, dockerCli.Client())
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := addServiceConfigs(ctx, dockerCli, namespace, services, composeConfigs, configs, composeServiceConfigs); err != nil {
+		return nil, err
 	}
-	return deployServices(ctx, dockerCli, services, namespace, opts.sendRegistryAuth)
+	images := map[string]string{}
+	if opts.resolveImage {
+		if images, err = pinImageDigests(ctx, dockerCli, services, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	results, err := deployServices(ctx, dockerCli, services, namespace, opts)
+	if err != nil {
+		return &DeployResult{Services: results, Images: images}, err
+	}
+
+	if err := pruneConfigs(ctx, dockerCli, namespace, configs, opts.convergeTimeout); err != nil {
+		fmt.Fprintf(dockerCli.Err(), "%s\n", err)
+	}
+	if opts.versionSecrets {
+		fmt.Fprintf(dockerCli.Out(), "Waiting for stack %s to converge before removing old secret versions\n", namespace.Name())
+		if err := pruneSecretVersions(ctx, dockerCli, namespace, secrets, opts.convergeTimeout); err != nil {
+			fmt.Fprintf(dockerCli.Err(), "%s\n", err)
+		}
+	}
+	snapshot := newSnapshot(opts, config, networks, services)
+	snapshot.Configs = configs
+	return &DeployResult{
+		Services: results,
+		Images:   images,
+		snapshot: snapshot,
+	}, nil
 }
 
 func getServicesDeclaredNetworks(serviceConfigs []composetypes.ServiceConfig) map[string]struct{} {
@@ -104,7 +164,7 @@ func getConfigDetails(opts DeployOptions) (composetypes.ConfigDetails, error) {
 	var details composetypes.ConfigDetails
 	var err error
 
-	details.WorkingDir, err = os.Getwd()
+	details.WorkingDir, err = composeWorkingDir(opts.composefile)
 	if err != nil {
 		return details, err
 	}
@@ -118,6 +178,12 @@ func getConfigDetails(opts DeployOptions) (composetypes.ConfigDetails, error) {
 	return details, nil
 }
 
+// composeWorkingDir is the directory of the compose file, which all relative
+// paths in the compose file are relative to, whatever the current directory
+func composeWorkingDir(composefile string) (string, error) {
+	return filepath.Abs(filepath.Dir(composefile))
+}
+
 func getConfigFile(filename string) (*composetypes.ConfigFile, error) {
 	bytes, err := ioutil.ReadFile(filename)
 	if err != nil {
@@ -166,6 +232,10 @@ func createSecrets(
 	for _, secretSpec := range secrets {
 		secret, _, err := client.SecretInspectWithRaw(ctx, secretSpec.Name)
 		if err == nil {
+			if _, versioned := secretSpec.Labels[labelSecretName]; versioned {
+				// a versioned secret with the same name has the same data
+				continue
+			}
 			// secret already exists, then we update that
 			if err := client.SecretUpdate(ctx, secret.ID, secret.Meta.Version, secretSpec); err != nil {
 				return err
@@ -219,19 +289,23 @@ func createNetworks(
 	return nil
 }
 
+// deployServices creates or updates services using a pool of workers.  The
+// services are handled in name order, and the output of each service is
+// written in that order, so that the output is the same on every run.  A
+// failing service does not stop the others, instead all failures are
+// returned together.
 func deployServices(
 	ctx context.Context,
 	dockerCli *command.DockerCli,
 	services map[string]swarm.ServiceSpec,
 	namespace convert.Namespace,
-	sendAuth bool,
-) error {
+	opts DeployOptions,
+) (ServiceResults, error) {
 	apiClient := dockerCli.Client()
-	out := dockerCli.Out()
 
 	existingServices, err := getServices(ctx, apiClient, namespace.Name())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	existingServiceMap := make(map[string]swarm.Service)
@@ -239,52 +313,131 @@ func deployServices(
 		existingServiceMap[service.Spec.Name] = service
 	}
 
-	for internalName, serviceSpec := range services {
-		name := namespace.Scope(internalName)
+	internalNames := make([]string, 0, len(services))
+	for internalName := range services {
+		internalNames = append(internalNames, internalName)
+	}
+	sort.Strings(internalNames)
 
-		encodedAuth := ""
-		if sendAuth {
-			// Retrieve encoded auth token from the image reference
-			image := serviceSpec.TaskTemplate.ContainerSpec.Image
-			encodedAuth, err = command.RetrieveAuthTokenFromImage(ctx, dockerCli, image)
-			if err != nil {
-				return err
-			}
-		}
+	parallelism := opts.parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
 
-		if service, exists := existingServiceMap[name]; exists {
-			fmt.Fprintf(out, "Updating service %s (id: %s)\n", name, service.ID)
+	results := make(ServiceResults, len(internalNames))
+	outputs := make([]deployServiceOutput, len(internalNames))
+	done := make([]chan struct{}, len(internalNames))
+	for index := range internalNames {
+		done[index] = make(chan struct{})
+	}
 
-			updateOpts := types.ServiceUpdateOptions{}
-			if sendAuth {
-				updateOpts.EncodedRegistryAuth = encodedAuth
-			}
-			response, err := apiClient.ServiceUpdate(
-				ctx,
-				service.ID,
-				service.Version,
-				serviceSpec,
-				updateOpts,
-			)
-			if err != nil {
-				return err
+	jobs := make(chan int)
+	go func() {
+		for index := range internalNames {
+			jobs <- index
+		}
+		close(jobs)
+	}()
+
+	for worker := 0; worker < parallelism; worker++ {
+		go func() {
+			for index := range jobs {
+				name := namespace.Scope(internalNames[index])
+				// a missing service gives an empty ID, so it will be created
+				service := existingServiceMap[name]
+				results[index] = deployService(ctx, dockerCli, &outputs[index], name, services[internalNames[index]], service, opts)
+				close(done[index])
 			}
+		}()
+	}
+
+	// write the output of each service as soon as all services before it are done
+	for index := range internalNames {
+		<-done[index]
+		outputs[index].out.WriteTo(dockerCli.Out())
+		outputs[index].err.WriteTo(dockerCli.Err())
+	}
+
+	if err := results.Err(); err != nil || !opts.monitorRollout {
+		return results, err
+	}
+	return monitorRollouts(ctx, dockerCli, results, existingServices, opts.convergeTimeout)
+}
+
+// deployServiceOutput buffers the output of a single service deploy
+type deployServiceOutput struct {
+	out bytes.Buffer
+	err bytes.Buffer
+}
 
+// deployService creates a single service, or updates it if existing has an ID
+func deployService(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	output *deployServiceOutput,
+	name string,
+	serviceSpec swarm.ServiceSpec,
+	existing swarm.Service,
+	opts DeployOptions,
+) ServiceResult {
+	apiClient := dockerCli.Client()
+	result := ServiceResult{
+		Name: name,
+		ID:   existing.ID,
+	}
+
+	// Retrieve encoded auth token from the image reference
+	image := serviceSpec.TaskTemplate.ContainerSpec.Image
+	encodedAuth, err := opts.encodedRegistryAuth(ctx, dockerCli, image)
+	if err != nil {
+		result.Error = err
+		fmt.Fprintf(&output.err, "Failed to deploy service %s: %s\n", name, err)
+		return result
+	}
+
+	if existing.ID != "" {
+		result.Action = "update"
+		fmt.Fprintf(&output.out, "Updating service %s (id: %s)\n", name, existing.ID)
+
+		updateOpts := types.ServiceUpdateOptions{
+			EncodedRegistryAuth: encodedAuth,
+		}
+		response, retries, err := updateServiceWithRetry(
+			ctx,
+			apiClient,
+			existing,
+			opts.updateRetries,
+			func(swarm.Service) swarm.ServiceSpec { return serviceSpec },
+			updateOpts,
+		)
+		result.Retries = retries
+		if retries > 0 {
+			fmt.Fprintf(&output.out, "Service %s was changed during the update, and needed %d retries\n", name, retries)
+		}
+		if err != nil {
+			result.Error = err
+		} else {
 			for _, warning := range response.Warnings {
-				fmt.Fprintln(dockerCli.Err(), warning)
+				fmt.Fprintln(&output.err, warning)
 			}
-		} else {
-			fmt.Fprintf(out, "Creating service %s\n", name)
+		}
+	} else {
+		result.Action = "create"
+		fmt.Fprintf(&output.out, "Creating service %s\n", name)
 
-			createOpts := types.ServiceCreateOptions{}
-			if sendAuth {
-				createOpts.EncodedRegistryAuth = encodedAuth
-			}
-			if _, err := apiClient.ServiceCreate(ctx, serviceSpec, createOpts); err != nil {
-				return err
-			}
+		createOpts := types.ServiceCreateOptions{
+			EncodedRegistryAuth: encodedAuth,
+		}
+		response, err := apiClient.ServiceCreate(ctx, serviceSpec, createOpts)
+		if err != nil {
+			result.Error = err
+		} else {
+			result.ID = response.ID
 		}
 	}
 
-	return nil
+	if result.Error != nil {
+		fmt.Fprintf(&output.err, "Failed to deploy service %s: %s\n", name, result.Error)
+	}
+	return result
 }
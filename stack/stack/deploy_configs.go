@@ -0,0 +1,422 @@
+package stack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/cli/compose/convert"
+	composetypes "github.com/docker/docker/cli/compose/types"
+	apiclient "github.com/docker/docker/client"
+)
+
+const (
+	// Label holding the compose name of a versioned config
+	labelConfigName = "com.wunderkraut.radi.dockercli.config.name"
+	// Length of the content hash appended to config names
+	configVersionLength = 8
+)
+
+/**
+ * Swarm configs
+ *
+ * The compose loader does not know about top level or service `configs:`
+ * so they are taken out of the parsed compose file before it is loaded, and
+ * converted here, the same way that secrets are handled.
+ *
+ * Swarm does not allow the data of a config to change, so every config is
+ * versioned by a hash of its content, such as ns_nginx_0a1b2c3d, in the same
+ * way as versioned secrets.  Versions that are no longer used are removed
+ * once the services have converged.
+ */
+
+// composeConfig is a top level compose config
+type composeConfig struct {
+	File     string
+	External bool
+	// Name is the swarm name for external configs
+	Name   string
+	Labels map[string]string
+}
+
+// composeServiceConfig is a config reference in a compose service
+type composeServiceConfig struct {
+	Source string
+	Target string
+	UID    string
+	GID    string
+	Mode   *uint32
+}
+
+// extractComposeConfigs removes the top level and service configs from the
+// compose files, returning them keyed by config name and service name
+func extractComposeConfigs(details *composetypes.ConfigDetails) (map[string]composeConfig, map[string][]composeServiceConfig, error) {
+	configs := map[string]composeConfig{}
+	serviceConfigs := map[string][]composeServiceConfig{}
+
+	for _, configFile := range details.ConfigFiles {
+		if raw, exists := configFile.Config["configs"]; exists {
+			delete(configFile.Config, "configs")
+
+			rawConfigs, ok := raw.(map[string]interface{})
+			if !ok {
+				return nil, nil, fmt.Errorf("configs must be a mapping in %s", configFile.Filename)
+			}
+			for name, rawConfig := range rawConfigs {
+				config, err := parseComposeConfig(name, rawConfig)
+				if err != nil {
+					return nil, nil, err
+				}
+				configs[name] = config
+			}
+		}
+
+		services, _ := configFile.Config["services"].(map[string]interface{})
+		for serviceName, rawService := range services {
+			service, ok := rawService.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			raw, exists := service["configs"]
+			if !exists {
+				continue
+			}
+			delete(service, "configs")
+
+			rawList, ok := raw.([]interface{})
+			if !ok {
+				return nil, nil, fmt.Errorf("configs for service %s must be a list", serviceName)
+			}
+			for _, rawServiceConfig := range rawList {
+				serviceConfig, err := parseComposeServiceConfig(serviceName, rawServiceConfig)
+				if err != nil {
+					return nil, nil, err
+				}
+				serviceConfigs[serviceName] = append(serviceConfigs[serviceName], serviceConfig)
+			}
+		}
+	}
+
+	return configs, serviceConfigs, nil
+}
+
+func parseComposeConfig(name string, raw interface{}) (composeConfig, error) {
+	config := composeConfig{}
+
+	values, ok := raw.(map[string]interface{})
+	if !ok {
+		return config, fmt.Errorf("config %s must be a mapping", name)
+	}
+
+	if file, ok := values["file"].(string); ok {
+		config.File = file
+	}
+
+	switch external := values["external"].(type) {
+	case bool:
+		config.External = external
+	case map[string]interface{}:
+		config.External = true
+		config.Name, _ = external["name"].(string)
+	}
+	if config.External && config.Name == "" {
+		config.Name = name
+	}
+
+	switch labels := values["labels"].(type) {
+	case map[string]interface{}:
+		config.Labels = map[string]string{}
+		for key, value := range labels {
+			config.Labels[key] = fmt.Sprint(value)
+		}
+	case []interface{}:
+		config.Labels = map[string]string{}
+		for _, label := range labels {
+			parts := strings.SplitN(fmt.Sprint(label), "=", 2)
+			if len(parts) == 1 {
+				parts = append(parts, "")
+			}
+			config.Labels[parts[0]] = parts[1]
+		}
+	}
+
+	if !config.External && config.File == "" {
+		return config, fmt.Errorf("config %s needs either a file or to be external", name)
+	}
+	return config, nil
+}
+
+func parseComposeServiceConfig(serviceName string, raw interface{}) (composeServiceConfig, error) {
+	serviceConfig := composeServiceConfig{}
+
+	switch values := raw.(type) {
+	case string:
+		serviceConfig.Source = values
+	case map[string]interface{}:
+		serviceConfig.Source, _ = values["source"].(string)
+		serviceConfig.Target, _ = values["target"].(string)
+		if uid, exists := values["uid"]; exists {
+			serviceConfig.UID = fmt.Sprint(uid)
+		}
+		if gid, exists := values["gid"]; exists {
+			serviceConfig.GID = fmt.Sprint(gid)
+		}
+		if mode, exists := values["mode"]; exists {
+			parsed, err := strconv.ParseUint(fmt.Sprint(mode), 0, 32)
+			if err != nil {
+				return serviceConfig, fmt.Errorf("invalid mode for config %s in service %s: %v", serviceConfig.Source, serviceName, err)
+			}
+			mode := uint32(parsed)
+			serviceConfig.Mode = &mode
+		}
+	}
+
+	if serviceConfig.Source == "" {
+		return serviceConfig, fmt.Errorf("config for service %s has no source", serviceName)
+	}
+	return serviceConfig, nil
+}
+
+// convertConfigs reads the config files and creates the versioned swarm
+// config specs for all configs that are not external.  Relative files are
+// relative to the compose working directory.
+func convertConfigs(namespace convert.Namespace, workingDir string, configs map[string]composeConfig) ([]swarm.ConfigSpec, error) {
+	var names []string
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := []swarm.ConfigSpec{}
+	for _, name := range names {
+		config := configs[name]
+		if config.External {
+			continue
+		}
+
+		file := config.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(workingDir, file)
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		labels := map[string]string{}
+		for key, value := range config.Labels {
+			labels[key] = value
+		}
+		labels[labelConfigName] = name
+
+		hash := sha256.Sum256(data)
+		version := name + "_" + hex.EncodeToString(hash[:])[:configVersionLength]
+
+		result = append(result, swarm.ConfigSpec{
+			Annotations: swarm.Annotations{
+				Name:   namespace.Scope(version),
+				Labels: convert.AddStackLabel(namespace, labels),
+			},
+			Data: data,
+		})
+	}
+	return result, nil
+}
+
+// addServiceConfigs adds config references to the converted service specs
+func addServiceConfigs(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	namespace convert.Namespace,
+	services map[string]swarm.ServiceSpec,
+	configs map[string]composeConfig,
+	configSpecs []swarm.ConfigSpec,
+	serviceConfigs map[string][]composeServiceConfig,
+) error {
+	client := dockerCli.Client()
+
+	versions := map[string]string{}
+	for _, configSpec := range configSpecs {
+		versions[configSpec.Labels[labelConfigName]] = configSpec.Name
+	}
+
+	for serviceName, references := range serviceConfigs {
+		serviceSpec, exists := services[serviceName]
+		if !exists {
+			continue
+		}
+
+		for _, reference := range references {
+			config, exists := configs[reference.Source]
+			if !exists {
+				return fmt.Errorf("service %s uses undefined config %s", serviceName, reference.Source)
+			}
+
+			name := versions[reference.Source]
+			if config.External {
+				name = config.Name
+			}
+
+			swarmConfig, _, err := client.ConfigInspectWithRaw(ctx, name)
+			if err != nil {
+				return fmt.Errorf("config %s for service %s could not be found: %v", name, serviceName, err)
+			}
+
+			target := reference.Target
+			if target == "" {
+				target = "/" + reference.Source
+			}
+			mode := os.FileMode(0444)
+			if reference.Mode != nil {
+				mode = os.FileMode(*reference.Mode)
+			}
+			uid := reference.UID
+			if uid == "" {
+				uid = "0"
+			}
+			gid := reference.GID
+			if gid == "" {
+				gid = "0"
+			}
+
+			serviceSpec.TaskTemplate.ContainerSpec.Configs = append(serviceSpec.TaskTemplate.ContainerSpec.Configs, &swarm.ConfigReference{
+				File: &swarm.ConfigReferenceFileTarget{
+					Name: target,
+					UID:  uid,
+					GID:  gid,
+					Mode: mode,
+				},
+				ConfigID:   swarmConfig.ID,
+				ConfigName: name,
+			})
+		}
+
+		services[serviceName] = serviceSpec
+	}
+	return nil
+}
+
+func createConfigs(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	namespace convert.Namespace,
+	configs []swarm.ConfigSpec,
+) error {
+	client := dockerCli.Client()
+
+	for _, configSpec := range configs {
+		config, _, err := client.ConfigInspectWithRaw(ctx, configSpec.Name)
+		if err == nil {
+			// a config version with the same name has the same data, so
+			// only its labels can have changed
+			if labelsEqual(config.Spec.Labels, configSpec.Labels) {
+				continue
+			}
+			update := config.Spec
+			update.Labels = configSpec.Labels
+			if err := client.ConfigUpdate(ctx, config.ID, config.Meta.Version, update); err != nil {
+				return err
+			}
+		} else if apiclient.IsErrNotFound(err) {
+			// config does not exist, then we create a new one.
+			fmt.Fprintf(dockerCli.Out(), "Creating config %s\n", configSpec.Name)
+			if _, err := client.ConfigCreate(ctx, configSpec); err != nil {
+				return err
+			}
+		} else {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneConfigs removes the stack configs that are no longer declared, which
+// are old versions and configs removed from the compose file.  Tasks may
+// still use them until the stack has converged, so that is waited for first.
+func pruneConfigs(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	namespace convert.Namespace,
+	configs []swarm.ConfigSpec,
+	timeout time.Duration,
+) error {
+	client := dockerCli.Client()
+
+	existingConfigs, err := getStackConfigs(ctx, client, namespace.Name())
+	if err != nil {
+		return err
+	}
+
+	declared := map[string]struct{}{}
+	for _, configSpec := range configs {
+		declared[configSpec.Name] = struct{}{}
+	}
+
+	var undeclared []swarm.Config
+	for _, config := range existingConfigs {
+		if _, exists := declared[config.Spec.Name]; !exists {
+			undeclared = append(undeclared, config)
+		}
+	}
+	if len(undeclared) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "Waiting for stack %s to converge before removing old configs\n", namespace.Name())
+	if err := waitForConvergence(ctx, client, namespace.Name(), timeout); err != nil {
+		return fmt.Errorf("Not removing old configs: %s", err)
+	}
+
+	// swarm refuses to remove configs in use by any service, in any stack, and
+	// the previous specs keep their configs so that services can roll back
+	services, err := client.ServiceList(ctx, types.ServiceListOptions{})
+	if err != nil {
+		return err
+	}
+	used := map[string]struct{}{}
+	for _, service := range services {
+		for _, reference := range service.Spec.TaskTemplate.ContainerSpec.Configs {
+			used[reference.ConfigID] = struct{}{}
+		}
+		if service.PreviousSpec != nil {
+			for _, reference := range service.PreviousSpec.TaskTemplate.ContainerSpec.Configs {
+				used[reference.ConfigID] = struct{}{}
+			}
+		}
+	}
+
+	var unused []swarm.Config
+	for _, config := range undeclared {
+		if _, exists := used[config.ID]; !exists {
+			unused = append(unused, config)
+		}
+	}
+
+	if removeConfigs(ctx, dockerCli, unused) {
+		return fmt.Errorf("Failed to prune some configs")
+	}
+	return nil
+}
+
+func labelsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, exists := b[key]; !exists || other != value {
+			return false
+		}
+	}
+	return true
+}
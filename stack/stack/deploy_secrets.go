@@ -0,0 +1,155 @@
+package stack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/cli/compose/convert"
+	composetypes "github.com/docker/docker/cli/compose/types"
+)
+
+const (
+	// Label holding the compose name of a versioned secret
+	labelSecretName = "com.wunderkraut.radi.dockercli.secret.name"
+	// Length of the content hash appended to versioned secret names
+	secretVersionLength = 8
+)
+
+/**
+ * Versioned secrets
+ *
+ * Swarm does not allow the data of a secret to change, so instead each
+ * version of a secret gets its own name, with a hash of its content, such as
+ * ns_dbpass_0a1b2c3d.  Services are pointed at the new name, and versions
+ * that are no longer used are removed once the services have converged.
+ */
+
+// versionSecrets renames each stack secret to include a hash of its data, and
+// points the compose services at the versioned names
+func versionSecrets(namespace convert.Namespace, config *composetypes.Config, secrets []swarm.SecretSpec) []swarm.SecretSpec {
+	specs := map[string]swarm.SecretSpec{}
+	for _, secretSpec := range secrets {
+		specs[secretSpec.Name] = secretSpec
+	}
+
+	var names []string
+	for name := range config.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	versioned := []swarm.SecretSpec{}
+	versions := map[string]string{}
+	for _, name := range names {
+		secretConfig := config.Secrets[name]
+		secretSpec, exists := specs[namespace.Scope(name)]
+		if !exists || secretConfig.External.External {
+			continue
+		}
+
+		hash := sha256.Sum256(secretSpec.Data)
+		version := name + "_" + hex.EncodeToString(hash[:])[:secretVersionLength]
+		versions[name] = version
+
+		labels := map[string]string{}
+		for key, value := range secretSpec.Labels {
+			labels[key] = value
+		}
+		labels[labelSecretName] = name
+
+		secretSpec.Name = namespace.Scope(version)
+		secretSpec.Labels = labels
+		versioned = append(versioned, secretSpec)
+
+		// the converter looks secrets up by their compose name
+		config.Secrets[version] = secretConfig
+	}
+
+	for index, service := range config.Services {
+		for secretIndex, secret := range service.Secrets {
+			version, exists := versions[secret.Source]
+			if !exists {
+				continue
+			}
+			if secret.Target == "" {
+				// keep the file name that the service expects
+				secret.Target = secret.Source
+			}
+			secret.Source = version
+			service.Secrets[secretIndex] = secret
+		}
+		config.Services[index] = service
+	}
+
+	return versioned
+}
+
+// pruneSecretVersions waits for the stack to converge, so that no task uses an
+// old secret version any more, and then removes the versions that are no
+// longer used by any service.
+func pruneSecretVersions(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	namespace convert.Namespace,
+	secrets []swarm.SecretSpec,
+	timeout time.Duration,
+) error {
+	client := dockerCli.Client()
+
+	if err := waitForConvergence(ctx, client, namespace.Name(), timeout); err != nil {
+		return fmt.Errorf("Not removing old secret versions: %s", err)
+	}
+
+	existingSecrets, err := getStackSecrets(ctx, client, namespace.Name())
+	if err != nil {
+		return err
+	}
+
+	current := map[string]struct{}{}
+	for _, secretSpec := range secrets {
+		current[secretSpec.Name] = struct{}{}
+	}
+
+	// swarm refuses to remove secrets in use by any service, in any stack, and
+	// the previous specs keep their secrets so that services can roll back
+	services, err := client.ServiceList(ctx, types.ServiceListOptions{})
+	if err != nil {
+		return err
+	}
+	used := map[string]struct{}{}
+	for _, service := range services {
+		for _, reference := range service.Spec.TaskTemplate.ContainerSpec.Secrets {
+			used[reference.SecretID] = struct{}{}
+		}
+		if service.PreviousSpec != nil {
+			for _, reference := range service.PreviousSpec.TaskTemplate.ContainerSpec.Secrets {
+				used[reference.SecretID] = struct{}{}
+			}
+		}
+	}
+
+	var unused []swarm.Secret
+	for _, secret := range existingSecrets {
+		if _, versioned := secret.Spec.Labels[labelSecretName]; !versioned {
+			continue
+		}
+		if _, exists := current[secret.Spec.Name]; exists {
+			continue
+		}
+		if _, exists := used[secret.ID]; !exists {
+			unused = append(unused, secret)
+		}
+	}
+
+	if removeSecrets(ctx, dockerCli, unused) {
+		return fmt.Errorf("Failed to remove some old secret versions")
+	}
+	return nil
+}
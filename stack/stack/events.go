@@ -0,0 +1,290 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/cli/compose/convert"
+	"github.com/docker/docker/client"
+)
+
+const (
+	// Container labels that swarm adds to task containers
+	labelSwarmServiceName = "com.docker.swarm.service.name"
+	labelSwarmTaskName    = "com.docker.swarm.task.name"
+
+	// StackEvent types
+	EventTypeService   = "service"
+	EventTypeTask      = "task"
+	EventTypeContainer = "container"
+	EventTypeNetwork   = "network"
+	EventTypeSecret    = "secret"
+	EventTypeConfig    = "config"
+)
+
+/**
+ * Stack events
+ *
+ * Engine events are read for the object types that a stack is made of, and
+ * matched to the stack by the namespace label.  Service, network, secret and
+ * config events do not carry the labels of the object, so they are matched
+ * by the IDs of the stack objects instead, which are listed at the start and
+ * again whenever an object is created.  Swarm does not send task events, so
+ * events of task containers are reported as task events.
+ */
+
+type EventsOptions struct {
+	namespace string
+	since     string
+	until     string
+	types     []string
+
+	out    io.Writer
+	events func(StackEvent)
+}
+
+func New_EventsOptions(namespace string) *EventsOptions {
+	return &EventsOptions{
+		namespace: namespace,
+	}
+}
+
+// Also show past events since a timestamp or relative duration, such as 10m
+func (opts *EventsOptions) SetSince(since string) {
+	opts.since = since
+}
+
+// Stop at a timestamp or relative duration, instead of when the context is cancelled
+func (opts *EventsOptions) SetUntil(until string) {
+	opts.until = until
+}
+
+// Only show events of these types, such as service or task
+func (opts *EventsOptions) SetTypes(types []string) {
+	opts.types = types
+}
+
+// Write events to this writer, instead of the cli output
+func (opts *EventsOptions) SetOutput(out io.Writer) {
+	opts.out = out
+}
+
+// Receive the events, instead of writing them
+func (opts *EventsOptions) SetEvents(events func(StackEvent)) {
+	opts.events = events
+}
+
+// StackEvent is an engine event for an object in the stack
+type StackEvent struct {
+	Time time.Time
+	// Type is one of the EventType constants
+	Type   string
+	Action string
+	ID     string
+	Name   string
+	// Service is the service name in the stack, if the object belongs to one
+	Service string
+	// Task is the task name, for task events
+	Task       string
+	Attributes map[string]string
+}
+
+// Failed tells if the event is a crash, such as a container exiting with an
+// error or being killed for running out of memory
+func (event StackEvent) Failed() bool {
+	switch event.Action {
+	case "oom":
+		return true
+	case "die":
+		exitCode, exists := event.Attributes["exitCode"]
+		return exists && exitCode != "0"
+	}
+	return false
+}
+
+// RunEvents streams the stack events until the context is cancelled, or
+// the until time is reached
+func RunEvents(ctx context.Context, dockerCli *command.DockerCli, opts EventsOptions) error {
+	engineTypes, err := engineEventTypes(opts.types)
+	if err != nil {
+		return err
+	}
+
+	filter := filters.NewArgs()
+	for _, engineType := range engineTypes {
+		filter.Add("type", engineType)
+	}
+
+	messages, errs := dockerCli.Client().Events(ctx, types.EventsOptions{
+		Since:   opts.since,
+		Until:   opts.until,
+		Filters: filter,
+	})
+
+	handle := opts.events
+	if handle == nil {
+		out := opts.out
+		if out == nil {
+			out = dockerCli.Out()
+		}
+		handle = printStackEvent(out)
+	}
+
+	wanted := map[string]bool{}
+	for _, eventType := range opts.types {
+		wanted[eventType] = true
+	}
+
+	objects, err := stackObjectIDs(ctx, dockerCli.Client(), opts.namespace)
+	if err != nil {
+		return err
+	}
+
+	namespace := convert.NewNamespace(opts.namespace)
+	for {
+		select {
+		case message := <-messages:
+			if message.Action == "create" && message.Type != events.ContainerEventType {
+				if objects, err = stackObjectIDs(ctx, dockerCli.Client(), opts.namespace); err != nil {
+					return err
+				}
+			}
+			event, inStack := stackEvent(namespace, opts.namespace, objects, message)
+			if !inStack || (len(wanted) > 0 && !wanted[event.Type]) {
+				continue
+			}
+			handle(event)
+		case err := <-errs:
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// engineEventTypes maps stack event types to the engine event types that
+// they are read from
+func engineEventTypes(eventTypes []string) ([]string, error) {
+	if len(eventTypes) == 0 {
+		eventTypes = []string{EventTypeService, EventTypeTask, EventTypeContainer, EventTypeNetwork, EventTypeSecret, EventTypeConfig}
+	}
+
+	engineTypes := map[string]bool{}
+	for _, eventType := range eventTypes {
+		switch eventType {
+		case EventTypeService, EventTypeNetwork, EventTypeSecret, EventTypeConfig:
+			engineTypes[eventType] = true
+		case EventTypeTask, EventTypeContainer:
+			engineTypes[events.ContainerEventType] = true
+		default:
+			return nil, fmt.Errorf("Unknown event type %q", eventType)
+		}
+	}
+
+	list := []string{}
+	for engineType := range engineTypes {
+		list = append(list, engineType)
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// stackObjectIDs lists the IDs of the stack services, networks, secrets and
+// configs
+func stackObjectIDs(ctx context.Context, apiclient client.APIClient, namespace string) (map[string]bool, error) {
+	ids := map[string]bool{}
+
+	services, err := getServices(ctx, apiclient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range services {
+		ids[service.ID] = true
+	}
+
+	networks, err := getStackNetworks(ctx, apiclient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, network := range networks {
+		ids[network.ID] = true
+	}
+
+	secrets, err := getStackSecrets(ctx, apiclient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secrets {
+		ids[secret.ID] = true
+	}
+
+	configs, err := getStackConfigs(ctx, apiclient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		ids[config.ID] = true
+	}
+
+	return ids, nil
+}
+
+// stackEvent converts an engine event, telling if it belongs to the stack,
+// either by its namespace label or by being one of the stack objects
+func stackEvent(namespace convert.Namespace, name string, objects map[string]bool, message events.Message) (StackEvent, bool) {
+	attributes := message.Actor.Attributes
+	event := StackEvent{
+		Time:       time.Unix(0, message.TimeNano),
+		Type:       message.Type,
+		Action:     message.Action,
+		ID:         message.Actor.ID,
+		Name:       attributes["name"],
+		Attributes: attributes,
+	}
+
+	inStack := attributes[convert.LabelNamespace] == name
+	if !inStack && message.Type != events.ContainerEventType {
+		inStack = objects[message.Actor.ID]
+	}
+
+	switch message.Type {
+	case events.ContainerEventType:
+		if taskName, isTask := attributes[labelSwarmTaskName]; isTask {
+			event.Type = EventTypeTask
+			event.Task = taskName
+			event.Service = strings.TrimPrefix(attributes[labelSwarmServiceName], namespace.Scope(""))
+		} else {
+			event.Service = attributes[labelComposeService]
+		}
+	case EventTypeService:
+		event.Service = strings.TrimPrefix(event.Name, namespace.Scope(""))
+	}
+
+	return event, inStack
+}
+
+// printStackEvent writes one line per event
+func printStackEvent(out io.Writer) func(StackEvent) {
+	return func(event StackEvent) {
+		name := event.Name
+		if event.Task != "" {
+			name = event.Task
+		}
+
+		details := ""
+		if exitCode, exists := event.Attributes["exitCode"]; exists {
+			details = " exitCode=" + exitCode
+		}
+
+		fmt.Fprintf(out, "%s %s %s %s%s\n", event.Time.Format(time.RFC3339), event.Type, event.Action, name, details)
+	}
+}
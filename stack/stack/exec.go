@@ -0,0 +1,198 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+/**
+ * Exec in a stack service
+ *
+ * A running task of the service is picked, and the command is executed in
+ * its container.  The engine can only exec in containers on the node that
+ * the client is connected to.
+ */
+
+type ExecOptions struct {
+	namespace   string
+	service     string
+	command     []string
+	user        string
+	tty         bool
+	interactive bool
+}
+
+func New_ExecOptions(namespace string, tty bool, interactive bool) *ExecOptions {
+	return &ExecOptions{
+		namespace:   namespace,
+		tty:         tty,
+		interactive: interactive,
+	}
+}
+
+// The stack service to exec in, by its name with or without the namespace
+func (opts *ExecOptions) SetService(service string) {
+	opts.service = service
+}
+
+// The command and arguments to execute
+func (opts *ExecOptions) SetCommand(command []string) {
+	opts.command = command
+}
+
+// Execute the command as a different user than the container uses
+func (opts *ExecOptions) SetUser(user string) {
+	opts.user = user
+}
+
+// RunExec executes a command in a running task of a stack service, attached
+// to the cli streams, and returns the exit code of the command.
+func RunExec(ctx context.Context, dockerCli *command.DockerCli, opts ExecOptions) (int, error) {
+	client := dockerCli.Client()
+
+	if opts.service == "" {
+		return 0, fmt.Errorf("No service given to exec in")
+	}
+	if len(opts.command) == 0 {
+		return 0, fmt.Errorf("No command given to exec in service %s", opts.service)
+	}
+
+	// exec only reaches containers on the connected node
+	info, err := client.Info(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	task, err := runningTask(ctx, dockerCli, opts.namespace, opts.service, info.Swarm.NodeID)
+	if err != nil {
+		return 0, err
+	}
+	if task.NodeID != info.Swarm.NodeID {
+		nodeName := task.NodeID
+		if node, _, err := client.NodeInspectWithRaw(ctx, task.NodeID); err == nil {
+			nodeName = node.Description.Hostname
+		}
+		return 0, fmt.Errorf("The running tasks of service %s are on node %s, but the docker client is connected to node %s. Connect to that node to exec in the service", opts.service, nodeName, info.Name)
+	}
+
+	execConfig := types.ExecConfig{
+		User:         opts.user,
+		Tty:          opts.tty,
+		AttachStdin:  opts.interactive,
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          opts.command,
+	}
+
+	containerID := task.Status.ContainerStatus.ContainerID
+	response, err := client.ContainerExecCreate(ctx, containerID, execConfig)
+	if err != nil {
+		return 0, err
+	}
+	if response.ID == "" {
+		return 0, fmt.Errorf("exec ID empty")
+	}
+
+	if err := attachExec(ctx, dockerCli, response.ID, execConfig); err != nil {
+		return 0, err
+	}
+
+	inspect, err := client.ContainerExecInspect(ctx, response.ID)
+	if err != nil {
+		return 0, err
+	}
+	return inspect.ExitCode, nil
+}
+
+// runningTask finds the running task of a service with the lowest slot,
+// preferring the tasks on a node.  A task on another node is only returned if
+// none of the running tasks are on that node.
+func runningTask(ctx context.Context, dockerCli *command.DockerCli, namespace, name, nodeID string) (swarm.Task, error) {
+	client := dockerCli.Client()
+
+	services, err := getServices(ctx, client, namespace)
+	if err != nil {
+		return swarm.Task{}, err
+	}
+	services, err = filterServicesByName(services, namespace, name)
+	if err != nil {
+		return swarm.Task{}, err
+	}
+
+	taskFilter := filters.NewArgs()
+	taskFilter.Add("service", services[0].ID)
+	taskFilter.Add("desired-state", string(swarm.TaskStateRunning))
+
+	tasks, err := client.TaskList(ctx, types.TaskListOptions{Filters: taskFilter})
+	if err != nil {
+		return swarm.Task{}, err
+	}
+
+	var running *swarm.Task
+	for index, task := range tasks {
+		if task.Status.State != swarm.TaskStateRunning || task.Status.ContainerStatus.ContainerID == "" {
+			continue
+		}
+		if running == nil {
+			running = &tasks[index]
+			continue
+		}
+		onNode, runningOnNode := task.NodeID == nodeID, running.NodeID == nodeID
+		if (onNode && !runningOnNode) || (onNode == runningOnNode && task.Slot < running.Slot) {
+			running = &tasks[index]
+		}
+	}
+	if running == nil {
+		return swarm.Task{}, fmt.Errorf("Service %s has no running tasks", name)
+	}
+	return *running, nil
+}
+
+// attachExec starts an exec and connects it to the cli streams until the
+// command ends
+func attachExec(ctx context.Context, dockerCli *command.DockerCli, execID string, execConfig types.ExecConfig) error {
+	response, err := dockerCli.Client().ContainerExecAttach(ctx, execID, execConfig)
+	if err != nil {
+		return err
+	}
+	defer response.Close()
+
+	if execConfig.Tty && execConfig.AttachStdin && dockerCli.In().IsTerminal() {
+		if err := dockerCli.In().SetRawTerminal(); err != nil {
+			return err
+		}
+		defer dockerCli.In().RestoreTerminal()
+	}
+
+	outputDone := make(chan error, 1)
+	go func() {
+		var err error
+		if execConfig.Tty {
+			_, err = io.Copy(dockerCli.Out(), response.Reader)
+		} else {
+			_, err = stdcopy.StdCopy(dockerCli.Out(), dockerCli.Err(), response.Reader)
+		}
+		outputDone <- err
+	}()
+
+	if execConfig.AttachStdin {
+		go func() {
+			io.Copy(response.Conn, dockerCli.In())
+			response.CloseWrite()
+		}()
+	}
+
+	select {
+	case err := <-outputDone:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,558 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/cli/compose/convert"
+)
+
+const (
+	exportComposeVersion = "3.1"
+)
+
+type ExportOptions struct {
+	namespace string
+	// composefile is the path to write to, or empty to write to the cli output
+	composefile string
+}
+
+func New_ExportOptions(namespace string, composefile string) *ExportOptions {
+	return &ExportOptions{
+		namespace:   namespace,
+		composefile: composefile,
+	}
+}
+
+// RunExport reads the services, networks, secrets and configs that carry the
+// stack namespace label, and converts them back into a version 3.1 compose
+// file, so that a stack deployed by other tooling can be taken over.
+//
+// Secret data cannot be read back from the swarm, so secrets are exported as
+// external secrets using their existing names.  Configs are exported in the
+// same way, in the configs section that deploy reads alongside the compose
+// file.  The endpoint mode needs compose file version 3.3, so it is not
+// exported.
+func RunExport(dockerCli *command.DockerCli, opts ExportOptions) error {
+	namespace := convert.NewNamespace(opts.namespace)
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	services, err := getServices(ctx, client, namespace.Name())
+	if err != nil {
+		return err
+	}
+
+	networks, err := getStackNetworks(ctx, client, namespace.Name())
+	if err != nil {
+		return err
+	}
+
+	secrets, err := getStackSecrets(ctx, client, namespace.Name())
+	if err != nil {
+		return err
+	}
+
+	configs, err := getStackConfigs(ctx, client, namespace.Name())
+	if err != nil {
+		return err
+	}
+
+	if len(services)+len(networks)+len(secrets)+len(configs) == 0 {
+		return fmt.Errorf("Nothing found in stack: %s", namespace.Name())
+	}
+
+	exporter := stackExporter{
+		namespace:    namespace,
+		networkNames: map[string]string{},
+	}
+
+	compose, err := exporter.convert(ctx, dockerCli, services, networks, secrets, configs)
+	if err != nil {
+		return err
+	}
+
+	bytes, err := yaml.Marshal(compose)
+	if err != nil {
+		return err
+	}
+	bytes = append([]byte(fmt.Sprintf("# Exported from stack %s on %s\n", namespace.Name(), time.Now().Format(time.RFC3339))), bytes...)
+
+	if opts.composefile == "" {
+		_, err = dockerCli.Out().Write(bytes)
+		return err
+	}
+
+	if err := ioutil.WriteFile(opts.composefile, bytes, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(dockerCli.Out(), "Exported stack %s to %s\n", namespace.Name(), opts.composefile)
+	return nil
+}
+
+/**
+ * Reverse conversion from swarm specs to compose
+ */
+
+type stackExporter struct {
+	namespace convert.Namespace
+	// networkNames maps network IDs to compose network names
+	networkNames map[string]string
+}
+
+func (exporter *stackExporter) convert(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	services []swarm.Service,
+	networks []types.NetworkResource,
+	secrets []swarm.Secret,
+	configs []swarm.Config,
+) (*exportCompose, error) {
+	compose := exportCompose{
+		Version:  exportComposeVersion,
+		Services: map[string]exportService{},
+		Networks: map[string]exportNetwork{},
+		Volumes:  map[string]exportVolume{},
+		Secrets:  map[string]exportSecret{},
+		Configs:  map[string]exportConfig{},
+	}
+
+	for _, network := range networks {
+		name := exporter.descope(network.Name)
+		exporter.networkNames[network.ID] = name
+		compose.Networks[name] = exportNetwork{
+			Driver:     network.Driver,
+			DriverOpts: network.Options,
+			Internal:   network.Internal,
+			Labels:     exporter.labels(network.Labels),
+			Ipam:       exportIpamFrom(network.IPAM),
+		}
+	}
+
+	for _, secret := range secrets {
+		compose.Secrets[secret.Spec.Name] = exportSecret{External: true}
+	}
+
+	for _, config := range configs {
+		compose.Configs[config.Spec.Name] = exportConfig{External: true}
+	}
+
+	for _, service := range services {
+		name := exporter.descope(service.Spec.Name)
+		exported, err := exporter.service(ctx, dockerCli, service.Spec, &compose)
+		if err != nil {
+			return nil, err
+		}
+		compose.Services[name] = exported
+	}
+
+	return &compose, nil
+}
+
+// descope strips the stack namespace from a resource name
+func (exporter *stackExporter) descope(name string) string {
+	return strings.TrimPrefix(name, exporter.namespace.Name()+"_")
+}
+
+// labels removes the stack namespace label, which deploy will add back
+func (exporter *stackExporter) labels(labels map[string]string) map[string]string {
+	filtered := map[string]string{}
+	for key, value := range labels {
+		if key != convert.LabelNamespace {
+			filtered[key] = value
+		}
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+// network returns the compose name for a network attachment target, which
+// may be an ID, adding networks from outside the stack as external networks
+func (exporter *stackExporter) network(ctx context.Context, dockerCli *command.DockerCli, target string, compose *exportCompose) (string, error) {
+	if name, exists := exporter.networkNames[target]; exists {
+		return name, nil
+	}
+
+	network, err := dockerCli.Client().NetworkInspect(ctx, target)
+	if err != nil {
+		return "", err
+	}
+	if name, exists := exporter.networkNames[network.ID]; exists {
+		return name, nil
+	}
+
+	exporter.networkNames[network.ID] = network.Name
+	compose.Networks[network.Name] = exportNetwork{External: true}
+	return network.Name, nil
+}
+
+func (exporter *stackExporter) service(ctx context.Context, dockerCli *command.DockerCli, spec swarm.ServiceSpec, compose *exportCompose) (exportService, error) {
+	container := spec.TaskTemplate.ContainerSpec
+
+	service := exportService{
+		Image:       container.Image,
+		Entrypoint:  container.Command,
+		Command:     container.Args,
+		Environment: container.Env,
+		Labels:      exporter.labels(container.Labels),
+		WorkingDir:  container.Dir,
+		User:        container.User,
+		Hostname:    container.Hostname,
+		Tty:         container.TTY,
+		Deploy: exportDeploy{
+			Labels: exporter.labels(spec.Labels),
+		},
+	}
+
+	if container.StopGracePeriod != nil {
+		service.StopGracePeriod = container.StopGracePeriod.String()
+	}
+
+	if health := container.Healthcheck; health != nil {
+		service.Healthcheck = &exportHealthcheck{
+			Test:    health.Test,
+			Retries: health.Retries,
+		}
+		if health.Interval != 0 {
+			service.Healthcheck.Interval = health.Interval.String()
+		}
+		if health.Timeout != 0 {
+			service.Healthcheck.Timeout = health.Timeout.String()
+		}
+	}
+
+	for _, mnt := range container.Mounts {
+		volume := mnt.Source + ":" + mnt.Target
+		if mnt.Source == "" {
+			volume = mnt.Target
+		}
+
+		if mnt.Type == mount.TypeVolume && mnt.Source != "" {
+			name := exporter.descope(mnt.Source)
+			volume = name + ":" + mnt.Target
+
+			exported := exportVolume{}
+			if mnt.VolumeOptions != nil {
+				exported.Labels = exporter.labels(mnt.VolumeOptions.Labels)
+				if mnt.VolumeOptions.DriverConfig != nil {
+					exported.Driver = mnt.VolumeOptions.DriverConfig.Name
+					exported.DriverOpts = mnt.VolumeOptions.DriverConfig.Options
+				}
+			}
+			compose.Volumes[name] = exported
+		}
+		if mnt.ReadOnly {
+			volume += ":ro"
+		}
+		service.Volumes = append(service.Volumes, volume)
+	}
+
+	for _, secret := range container.Secrets {
+		exported := exportServiceSecret{
+			Source: secret.SecretName,
+		}
+		if secret.File != nil {
+			exported.Target = secret.File.Name
+			exported.UID = secret.File.UID
+			exported.GID = secret.File.GID
+			mode := uint32(secret.File.Mode)
+			exported.Mode = &mode
+		}
+		if _, exists := compose.Secrets[secret.SecretName]; !exists {
+			compose.Secrets[secret.SecretName] = exportSecret{External: true}
+		}
+		service.Secrets = append(service.Secrets, exported)
+	}
+
+	for _, config := range container.Configs {
+		exported := exportServiceConfig{
+			Source: config.ConfigName,
+		}
+		if config.File != nil {
+			exported.Target = config.File.Name
+			exported.UID = config.File.UID
+			exported.GID = config.File.GID
+			mode := uint32(config.File.Mode)
+			exported.Mode = &mode
+		}
+		if _, exists := compose.Configs[config.ConfigName]; !exists {
+			compose.Configs[config.ConfigName] = exportConfig{External: true}
+		}
+		service.Configs = append(service.Configs, exported)
+	}
+
+	if len(spec.Networks) > 0 {
+		service.Networks = map[string]*exportServiceNetwork{}
+		for _, attachment := range spec.Networks {
+			name, err := exporter.network(ctx, dockerCli, attachment.Target, compose)
+			if err != nil {
+				return service, err
+			}
+
+			// the service name is always added as an alias during deploy
+			var aliases []string
+			for _, alias := range attachment.Aliases {
+				if alias != exporter.descope(spec.Name) {
+					aliases = append(aliases, alias)
+				}
+			}
+			if len(aliases) == 0 {
+				service.Networks[name] = nil
+			} else {
+				service.Networks[name] = &exportServiceNetwork{Aliases: aliases}
+			}
+		}
+	}
+
+	if spec.EndpointSpec != nil {
+		ports := append([]swarm.PortConfig{}, spec.EndpointSpec.Ports...)
+		sort.Sort(exportPortsByTarget(ports))
+		for _, port := range ports {
+			service.Ports = append(service.Ports, exportPortFrom(port))
+		}
+	}
+
+	switch {
+	case spec.Mode.Global != nil:
+		service.Deploy.Mode = "global"
+	case spec.Mode.Replicated != nil:
+		service.Deploy.Replicas = spec.Mode.Replicated.Replicas
+	}
+
+	if update := spec.UpdateConfig; update != nil {
+		service.Deploy.UpdateConfig = &exportUpdateConfig{
+			Parallelism:     &update.Parallelism,
+			FailureAction:   update.FailureAction,
+			MaxFailureRatio: update.MaxFailureRatio,
+		}
+		if update.Delay != 0 {
+			service.Deploy.UpdateConfig.Delay = update.Delay.String()
+		}
+		if update.Monitor != 0 {
+			service.Deploy.UpdateConfig.Monitor = update.Monitor.String()
+		}
+	}
+
+	if restart := spec.TaskTemplate.RestartPolicy; restart != nil {
+		service.Deploy.RestartPolicy = &exportRestartPolicy{
+			Condition:   string(restart.Condition),
+			MaxAttempts: restart.MaxAttempts,
+		}
+		if restart.Delay != nil {
+			service.Deploy.RestartPolicy.Delay = restart.Delay.String()
+		}
+		if restart.Window != nil {
+			service.Deploy.RestartPolicy.Window = restart.Window.String()
+		}
+	}
+
+	if resources := spec.TaskTemplate.Resources; resources != nil {
+		service.Deploy.Resources = &exportResourceRequirements{
+			Limits:       exportResourcesFrom(resources.Limits),
+			Reservations: exportResourcesFrom(resources.Reservations),
+		}
+	}
+
+	if placement := spec.TaskTemplate.Placement; placement != nil && len(placement.Constraints) > 0 {
+		service.Deploy.Placement = &exportPlacement{
+			Constraints: placement.Constraints,
+		}
+	}
+
+	return service, nil
+}
+
+func exportPortFrom(port swarm.PortConfig) string {
+	exported := strconv.FormatUint(uint64(port.TargetPort), 10)
+	if port.PublishedPort != 0 {
+		exported = strconv.FormatUint(uint64(port.PublishedPort), 10) + ":" + exported
+	}
+	if port.Protocol != "" && port.Protocol != swarm.PortConfigProtocolTCP {
+		exported += "/" + string(port.Protocol)
+	}
+	return exported
+}
+
+func exportResourcesFrom(resources *swarm.Resources) *exportResources {
+	if resources == nil {
+		return nil
+	}
+
+	exported := exportResources{}
+	if resources.NanoCPUs != 0 {
+		exported.NanoCPUs = strconv.FormatFloat(float64(resources.NanoCPUs)/1e9, 'f', -1, 64)
+	}
+	if resources.MemoryBytes != 0 {
+		exported.MemoryBytes = strconv.FormatInt(resources.MemoryBytes, 10)
+	}
+	return &exported
+}
+
+func exportIpamFrom(ipam network.IPAM) *exportIpam {
+	if ipam.Driver == "" && len(ipam.Config) == 0 {
+		return nil
+	}
+
+	exported := exportIpam{
+		Driver: ipam.Driver,
+	}
+	for _, config := range ipam.Config {
+		if config.Subnet != "" {
+			exported.Config = append(exported.Config, exportIpamPool{Subnet: config.Subnet})
+		}
+	}
+	return &exported
+}
+
+/**
+ * YML structs for the exported compose file
+ */
+
+type exportCompose struct {
+	Version  string                   `yaml:"version"`
+	Services map[string]exportService `yaml:"services,omitempty"`
+	Networks map[string]exportNetwork `yaml:"networks,omitempty"`
+	Volumes  map[string]exportVolume  `yaml:"volumes,omitempty"`
+	Secrets  map[string]exportSecret  `yaml:"secrets,omitempty"`
+	// Configs are not part of compose file version 3.1, deploy takes them
+	// out of the file before it is loaded
+	Configs map[string]exportConfig `yaml:"configs,omitempty"`
+}
+
+type exportService struct {
+	Image           string                           `yaml:"image"`
+	Entrypoint      []string                         `yaml:"entrypoint,omitempty"`
+	Command         []string                         `yaml:"command,omitempty"`
+	Environment     []string                         `yaml:"environment,omitempty"`
+	Labels          map[string]string                `yaml:"labels,omitempty"`
+	WorkingDir      string                           `yaml:"working_dir,omitempty"`
+	User            string                           `yaml:"user,omitempty"`
+	Hostname        string                           `yaml:"hostname,omitempty"`
+	Tty             bool                             `yaml:"tty,omitempty"`
+	StopGracePeriod string                           `yaml:"stop_grace_period,omitempty"`
+	Healthcheck     *exportHealthcheck               `yaml:"healthcheck,omitempty"`
+	Ports           []string                         `yaml:"ports,omitempty"`
+	Networks        map[string]*exportServiceNetwork `yaml:"networks,omitempty"`
+	Volumes         []string                         `yaml:"volumes,omitempty"`
+	Secrets         []exportServiceSecret            `yaml:"secrets,omitempty"`
+	Configs         []exportServiceConfig            `yaml:"configs,omitempty"`
+	Deploy          exportDeploy                     `yaml:"deploy,omitempty"`
+}
+
+type exportHealthcheck struct {
+	Test     []string `yaml:"test,omitempty"`
+	Interval string   `yaml:"interval,omitempty"`
+	Timeout  string   `yaml:"timeout,omitempty"`
+	Retries  int      `yaml:"retries,omitempty"`
+}
+
+type exportServiceNetwork struct {
+	Aliases []string `yaml:"aliases,omitempty"`
+}
+
+type exportServiceSecret struct {
+	Source string  `yaml:"source"`
+	Target string  `yaml:"target,omitempty"`
+	UID    string  `yaml:"uid,omitempty"`
+	GID    string  `yaml:"gid,omitempty"`
+	Mode   *uint32 `yaml:"mode,omitempty"`
+}
+
+type exportServiceConfig struct {
+	Source string  `yaml:"source"`
+	Target string  `yaml:"target,omitempty"`
+	UID    string  `yaml:"uid,omitempty"`
+	GID    string  `yaml:"gid,omitempty"`
+	Mode   *uint32 `yaml:"mode,omitempty"`
+}
+
+type exportDeploy struct {
+	Mode          string                      `yaml:"mode,omitempty"`
+	Replicas      *uint64                     `yaml:"replicas,omitempty"`
+	Labels        map[string]string           `yaml:"labels,omitempty"`
+	UpdateConfig  *exportUpdateConfig         `yaml:"update_config,omitempty"`
+	Resources     *exportResourceRequirements `yaml:"resources,omitempty"`
+	RestartPolicy *exportRestartPolicy        `yaml:"restart_policy,omitempty"`
+	Placement     *exportPlacement            `yaml:"placement,omitempty"`
+}
+
+type exportUpdateConfig struct {
+	Parallelism     *uint64 `yaml:"parallelism,omitempty"`
+	Delay           string  `yaml:"delay,omitempty"`
+	FailureAction   string  `yaml:"failure_action,omitempty"`
+	Monitor         string  `yaml:"monitor,omitempty"`
+	MaxFailureRatio float32 `yaml:"max_failure_ratio,omitempty"`
+}
+
+type exportResourceRequirements struct {
+	Limits       *exportResources `yaml:"limits,omitempty"`
+	Reservations *exportResources `yaml:"reservations,omitempty"`
+}
+
+type exportResources struct {
+	NanoCPUs    string `yaml:"cpus,omitempty"`
+	MemoryBytes string `yaml:"memory,omitempty"`
+}
+
+type exportRestartPolicy struct {
+	Condition   string  `yaml:"condition,omitempty"`
+	Delay       string  `yaml:"delay,omitempty"`
+	MaxAttempts *uint64 `yaml:"max_attempts,omitempty"`
+	Window      string  `yaml:"window,omitempty"`
+}
+
+type exportPlacement struct {
+	Constraints []string `yaml:"constraints,omitempty"`
+}
+
+type exportNetwork struct {
+	Driver     string            `yaml:"driver,omitempty"`
+	DriverOpts map[string]string `yaml:"driver_opts,omitempty"`
+	Internal   bool              `yaml:"internal,omitempty"`
+	Labels     map[string]string `yaml:"labels,omitempty"`
+	Ipam       *exportIpam       `yaml:"ipam,omitempty"`
+	External   bool              `yaml:"external,omitempty"`
+}
+
+type exportIpam struct {
+	Driver string           `yaml:"driver,omitempty"`
+	Config []exportIpamPool `yaml:"config,omitempty"`
+}
+
+type exportIpamPool struct {
+	Subnet string `yaml:"subnet"`
+}
+
+type exportVolume struct {
+	Driver     string            `yaml:"driver,omitempty"`
+	DriverOpts map[string]string `yaml:"driver_opts,omitempty"`
+	Labels     map[string]string `yaml:"labels,omitempty"`
+}
+
+type exportSecret struct {
+	External bool `yaml:"external"`
+}
+
+type exportConfig struct {
+	External bool `yaml:"external"`
+}
+
+type exportPortsByTarget []swarm.PortConfig
+
+func (n exportPortsByTarget) Len() int           { return len(n) }
+func (n exportPortsByTarget) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
+func (n exportPortsByTarget) Less(i, j int) bool { return n[i].TargetPort < n[j].TargetPort }
@@ -0,0 +1,96 @@
+package stack
+
+import (
+	"context"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/cli/compose/convert"
+	"github.com/docker/docker/cli/compose/loader"
+	composetypes "github.com/docker/docker/cli/compose/types"
+)
+
+// TestExportLoads checks that an exported stack loads the same way that
+// deploy loads a compose file
+func TestExportLoads(t *testing.T) {
+	replicas := uint64(2)
+	services := []swarm.Service{{
+		Spec: swarm.ServiceSpec{
+			Annotations: swarm.Annotations{
+				Name:   "app_web",
+				Labels: map[string]string{convert.LabelNamespace: "app"},
+			},
+			TaskTemplate: swarm.TaskSpec{
+				ContainerSpec: swarm.ContainerSpec{
+					Image: "nginx:1.13",
+					Secrets: []*swarm.SecretReference{{
+						SecretName: "app_password",
+						File:       &swarm.SecretReferenceFileTarget{Name: "password", UID: "0", GID: "0", Mode: 0400},
+					}},
+					Configs: []*swarm.ConfigReference{{
+						ConfigName: "app_nginx_0a1b2c3d",
+						File:       &swarm.ConfigReferenceFileTarget{Name: "/etc/nginx/nginx.conf", UID: "0", GID: "0", Mode: 0444},
+					}},
+				},
+			},
+			Networks: []swarm.NetworkAttachmentConfig{{Target: "network-id", Aliases: []string{"web"}}},
+			Mode:     swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+			EndpointSpec: &swarm.EndpointSpec{
+				Mode:  swarm.ResolutionModeVIP,
+				Ports: []swarm.PortConfig{{Protocol: swarm.PortConfigProtocolTCP, TargetPort: 80, PublishedPort: 8080}},
+			},
+		},
+	}}
+	networks := []types.NetworkResource{{ID: "network-id", Name: "app_default", Driver: "overlay"}}
+	secrets := []swarm.Secret{{Spec: swarm.SecretSpec{Annotations: swarm.Annotations{Name: "app_password"}}}}
+	configs := []swarm.Config{{Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: "app_nginx_0a1b2c3d"}}}}
+
+	exporter := stackExporter{
+		namespace:    convert.NewNamespace("app"),
+		networkNames: map[string]string{},
+	}
+	compose, err := exporter.convert(context.Background(), nil, services, networks, secrets, configs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported, err := yaml.Marshal(compose)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := loader.ParseYAML(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	details := composetypes.ConfigDetails{
+		WorkingDir:  ".",
+		ConfigFiles: []composetypes.ConfigFile{{Filename: "docker-compose.yml", Config: parsed}},
+	}
+	composeConfigs, composeServiceConfigs, err := extractComposeConfigs(&details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := loader.Load(details)
+	if err != nil {
+		t.Fatalf("exported compose file does not load: %s\n%s", err, exported)
+	}
+
+	if len(config.Services) != 1 || config.Services[0].Name != "web" || config.Services[0].Image != "nginx:1.13" {
+		t.Errorf("expected the web service to load, got %+v", config.Services)
+	}
+	if len(config.Services[0].Ports) != 1 {
+		t.Errorf("expected the published port to load, got %+v", config.Services[0].Ports)
+	}
+	if secret := config.Secrets["app_password"]; !secret.External.External {
+		t.Errorf("expected app_password to load as an external secret, got %+v", config.Secrets)
+	}
+	if config := composeConfigs["app_nginx_0a1b2c3d"]; !config.External || config.Name != "app_nginx_0a1b2c3d" {
+		t.Errorf("expected app_nginx_0a1b2c3d to load as an external config, got %+v", composeConfigs)
+	}
+	if references := composeServiceConfigs["web"]; len(references) != 1 || references[0].Target != "/etc/nginx/nginx.conf" {
+		t.Errorf("expected the web service to reference the config, got %+v", composeServiceConfigs)
+	}
+}
@@ -0,0 +1,40 @@
+package stack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// Output formats for stack commands that return structured data
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+)
+
+// isStructuredFormat tells if a format is rendered by writeStructured
+func isStructuredFormat(format string) bool {
+	return format == FormatJSON || format == FormatYAML
+}
+
+// writeStructured renders a value as indented JSON or as YAML
+func writeStructured(out io.Writer, format string, value interface{}) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case FormatYAML:
+		bytes, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(bytes)
+		return err
+	default:
+		return fmt.Errorf("Unknown format %q, expected %q or %q", format, FormatJSON, FormatYAML)
+	}
+}
@@ -0,0 +1,581 @@
+package stack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/cli/compose/convert"
+	"github.com/docker/docker/client"
+)
+
+const (
+	// Snapshot IDs are timestamps, so that they sort in deploy order
+	snapshotIdFormat = "20060102-150405.000"
+	// Number of unchanged lines shown around each change in a diff
+	snapshotDiffContext = 3
+
+	historyItemFmt = "%s\t%s\t%s\t%s\n"
+)
+
+/**
+ * Deploy history
+ *
+ * Every successful deploy can be recorded as a snapshot on disk, which
+ * can later be listed, compared and redeployed.
+ */
+
+// Snapshot is the record of a single successful stack deploy
+type Snapshot struct {
+	// ID identifies the snapshot in the history
+	ID string `json:"id"`
+	// Namespace is the stack namespace that was deployed
+	Namespace string `json:"namespace"`
+	// Created is when the deploy finished
+	Created time.Time `json:"created"`
+	// User is the local user who ran the deploy
+	User string `json:"user"`
+
+	// Bundlefile or Composefile that the deploy was built from
+	Bundlefile  string `json:"bundlefile,omitempty"`
+	Composefile string `json:"composefile,omitempty"`
+	// Config is the effective compose config (or bundle) after loading
+	Config interface{} `json:"config,omitempty"`
+
+	// Networks and Services are the converted specs that were deployed
+	Networks map[string]types.NetworkCreate `json:"networks"`
+	Services map[string]swarm.ServiceSpec   `json:"services"`
+	// Images maps service names to the image references the swarm resolved
+	Images map[string]string `json:"images"`
+	// Configs are the swarm configs that the services use.  Secrets are not
+	// recorded, as their data should not be written to disk.
+	Configs []swarm.ConfigSpec `json:"configs,omitempty"`
+}
+
+func newSnapshot(opts DeployOptions, config interface{}, networks map[string]types.NetworkCreate, services map[string]swarm.ServiceSpec) *Snapshot {
+	created := time.Now().UTC()
+
+	return &Snapshot{
+		ID:          created.Format(snapshotIdFormat),
+		Namespace:   opts.namespace,
+		Created:     created,
+		User:        currentUser(),
+		Bundlefile:  opts.bundlefile,
+		Composefile: opts.composefile,
+		Config:      config,
+		Networks:    networks,
+		Services:    services,
+		Images:      map[string]string{},
+	}
+}
+
+func currentUser() string {
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return os.Getenv("USER")
+}
+
+// recordSnapshot resolves the deployed image digests and saves the snapshot.
+// The deploy has already succeeded at this point, so failures are only
+// reported.
+func recordSnapshot(ctx context.Context, dockerCli *command.DockerCli, historyPath string, snapshot *Snapshot) {
+	if historyPath == "" || snapshot == nil {
+		return
+	}
+
+	if err := resolveSnapshotImages(ctx, dockerCli.Client(), snapshot); err != nil {
+		fmt.Fprintf(dockerCli.Err(), "Failed to resolve deployed images for snapshot: %s\n", err)
+	}
+
+	if err := New_SnapshotStore(historyPath).Save(snapshot); err != nil {
+		fmt.Fprintf(dockerCli.Err(), "Failed to record deploy snapshot: %s\n", err)
+		return
+	}
+	fmt.Fprintf(dockerCli.Out(), "Recorded deploy snapshot %s\n", snapshot.ID)
+}
+
+// resolveSnapshotImages reads back the image of each deployed service, which
+// the swarm manager will have pinned to a digest.
+func resolveSnapshotImages(ctx context.Context, apiclient client.APIClient, snapshot *Snapshot) error {
+	services, err := getServices(ctx, apiclient, snapshot.Namespace)
+	if err != nil {
+		return err
+	}
+
+	existingServiceMap := make(map[string]swarm.Service)
+	for _, service := range services {
+		existingServiceMap[service.Spec.Name] = service
+	}
+
+	namespace := convert.NewNamespace(snapshot.Namespace)
+	for internalName := range snapshot.Services {
+		if service, exists := existingServiceMap[namespace.Scope(internalName)]; exists {
+			snapshot.Images[internalName] = service.Spec.TaskTemplate.ContainerSpec.Image
+		}
+	}
+	return nil
+}
+
+/**
+ * Snapshot storage
+ */
+
+// SnapshotStore keeps snapshots as json files in a single folder
+type SnapshotStore struct {
+	path string
+}
+
+// Constructor for SnapshotStore
+func New_SnapshotStore(path string) *SnapshotStore {
+	return &SnapshotStore{
+		path: path,
+	}
+}
+
+// Save a snapshot to the store
+func (store *SnapshotStore) Save(snapshot *Snapshot) error {
+	if err := os.MkdirAll(store.path, 0755); err != nil {
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// snapshots hold service environments, so keep them private
+	return ioutil.WriteFile(store.file(snapshot.ID), bytes, 0600)
+}
+
+// List all snapshots for a namespace, oldest first
+func (store *SnapshotStore) List(namespace string) ([]*Snapshot, error) {
+	files, err := ioutil.ReadDir(store.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Snapshot{}, nil
+		}
+		return nil, err
+	}
+
+	snapshots := []*Snapshot{}
+	for _, file := range files {
+		if file.IsDir() || path.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		snapshot, err := store.load(path.Join(store.path, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if namespace == "" || snapshot.Namespace == namespace {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	sort.Sort(snapshotsByCreated(snapshots))
+	return snapshots, nil
+}
+
+// Get a single snapshot for a namespace.  An empty id retrieves the latest.
+func (store *SnapshotStore) Get(namespace, id string) (*Snapshot, error) {
+	snapshots, err := store.List(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := findSnapshot(snapshots, id)
+	if err != nil {
+		return nil, err
+	}
+	return snapshots[index], nil
+}
+
+func (store *SnapshotStore) file(id string) string {
+	return path.Join(store.path, id+".json")
+}
+
+func (store *SnapshotStore) load(file string) (*Snapshot, error) {
+	bytes, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := Snapshot{}
+	if err := json.Unmarshal(bytes, &snapshot); err != nil {
+		return nil, fmt.Errorf("Error reading snapshot %s: %v", file, err)
+	}
+	return &snapshot, nil
+}
+
+// findSnapshot returns the index of a snapshot, treating an empty id as latest
+func findSnapshot(snapshots []*Snapshot, id string) (int, error) {
+	if len(snapshots) == 0 {
+		return -1, fmt.Errorf("No deploy snapshots have been recorded")
+	}
+	if id == "" {
+		return len(snapshots) - 1, nil
+	}
+	for index, snapshot := range snapshots {
+		if snapshot.ID == id {
+			return index, nil
+		}
+	}
+	return -1, fmt.Errorf("Deploy snapshot %s not found", id)
+}
+
+type snapshotsByCreated []*Snapshot
+
+func (n snapshotsByCreated) Len() int           { return len(n) }
+func (n snapshotsByCreated) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
+func (n snapshotsByCreated) Less(i, j int) bool { return n[i].Created.Before(n[j].Created) }
+
+/**
+ * History commands
+ */
+
+type HistoryOptions struct {
+	historyPath      string
+	namespace        string
+	sendRegistryAuth bool
+
+	// registryAuth provides per image registry auth for redeploys
+	registryAuth RegistryAuthResolver
+}
+
+func New_HistoryOptions(historyPath string, namespace string, sendRegistryAuth bool) *HistoryOptions {
+	return &HistoryOptions{
+		historyPath:      historyPath,
+		namespace:        namespace,
+		sendRegistryAuth: sendRegistryAuth,
+	}
+}
+
+// Use a resolver to provide the registry auth for each redeployed service image
+func (opts *HistoryOptions) SetRegistryAuthResolver(resolver RegistryAuthResolver) {
+	opts.registryAuth = resolver
+}
+
+// RunHistoryList prints a table of all recorded snapshots for the stack
+func RunHistoryList(dockerCli *command.DockerCli, opts HistoryOptions) error {
+	snapshots, err := New_SnapshotStore(opts.historyPath).List(opts.namespace)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) == 0 {
+		fmt.Fprintf(dockerCli.Out(), "No deploy snapshots found for stack: %s\n", opts.namespace)
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(dockerCli.Out(), 0, 4, 2, ' ', 0)
+
+	// Ignore flushing errors
+	defer writer.Flush()
+
+	fmt.Fprintf(writer, historyItemFmt, "ID", "CREATED", "USER", "SERVICES")
+	for _, snapshot := range snapshots {
+		fmt.Fprintf(
+			writer,
+			historyItemFmt,
+			snapshot.ID,
+			snapshot.Created.Local().Format(time.RFC3339),
+			snapshot.User,
+			fmt.Sprint(len(snapshot.Services)),
+		)
+	}
+	return nil
+}
+
+// RunHistoryShow prints a single snapshot (the latest if id is empty)
+func RunHistoryShow(dockerCli *command.DockerCli, opts HistoryOptions, id string) error {
+	snapshot, err := New_SnapshotStore(opts.historyPath).Get(opts.namespace, id)
+	if err != nil {
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), string(bytes))
+	return nil
+}
+
+// RunHistoryDiff prints the differences between two snapshots.  If to is
+// empty then the latest snapshot is used, and if from is empty then the
+// snapshot before to is used.
+func RunHistoryDiff(dockerCli *command.DockerCli, opts HistoryOptions, from, to string) error {
+	snapshots, err := New_SnapshotStore(opts.historyPath).List(opts.namespace)
+	if err != nil {
+		return err
+	}
+
+	toIndex, err := findSnapshot(snapshots, to)
+	if err != nil {
+		return err
+	}
+
+	fromIndex := toIndex - 1
+	if from != "" {
+		if fromIndex, err = findSnapshot(snapshots, from); err != nil {
+			return err
+		}
+	} else if fromIndex < 0 {
+		return fmt.Errorf("Deploy snapshot %s has no earlier snapshot to compare with", snapshots[toIndex].ID)
+	}
+
+	lines := DiffSnapshots(snapshots[fromIndex], snapshots[toIndex])
+
+	out := dockerCli.Out()
+	if len(lines) == 0 {
+		fmt.Fprintf(out, "No differences between %s and %s\n", snapshots[fromIndex].ID, snapshots[toIndex].ID)
+		return nil
+	}
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", snapshots[fromIndex].ID, snapshots[toIndex].ID)
+	for _, line := range lines {
+		fmt.Fprintln(out, line)
+	}
+	return nil
+}
+
+// RunHistoryRedeploy deploys the exact specs and images of a prior snapshot.
+// Configs are recreated from the snapshot, but secrets are not kept in the
+// history, so they must still exist.
+func RunHistoryRedeploy(dockerCli *command.DockerCli, opts HistoryOptions, id string) error {
+	ctx := context.Background()
+
+	previous, err := New_SnapshotStore(opts.historyPath).Get(opts.namespace, id)
+	if err != nil {
+		return err
+	}
+
+	if err := checkDaemonIsSwarmManager(ctx, dockerCli); err != nil {
+		return err
+	}
+
+	namespace := convert.NewNamespace(previous.Namespace)
+
+	services := make(map[string]swarm.ServiceSpec)
+	for internalName, serviceSpec := range previous.Services {
+		if image, exists := previous.Images[internalName]; exists && image != "" {
+			serviceSpec.TaskTemplate.ContainerSpec.Image = image
+		}
+		services[internalName] = serviceSpec
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "Redeploying snapshot %s\n", previous.ID)
+
+	if err := createNetworks(ctx, dockerCli, namespace, previous.Networks); err != nil {
+		return err
+	}
+	if err := createConfigs(ctx, dockerCli, namespace, previous.Configs); err != nil {
+		return err
+	}
+	if err := relinkSnapshotObjects(ctx, dockerCli, services); err != nil {
+		return err
+	}
+	deployOpts := New_DeployOptions("", "", previous.Namespace, opts.sendRegistryAuth)
+	if opts.registryAuth != nil {
+		deployOpts.SetRegistryAuthResolver(opts.registryAuth)
+	}
+	if _, err := deployServices(ctx, dockerCli, services, namespace, *deployOpts); err != nil {
+		return err
+	}
+
+	snapshot := newSnapshot(DeployOptions{
+		bundlefile:  previous.Bundlefile,
+		composefile: previous.Composefile,
+		namespace:   previous.Namespace,
+	}, previous.Config, previous.Networks, services)
+	snapshot.Configs = previous.Configs
+	recordSnapshot(ctx, dockerCli, opts.historyPath, snapshot)
+	return nil
+}
+
+// relinkSnapshotObjects points the secret and config references of the
+// snapshot services at the current IDs of those objects, which change when
+// an object is removed and created again.  A secret that no longer exists,
+// such as an old secret version that was pruned, cannot be restored.
+func relinkSnapshotObjects(ctx context.Context, dockerCli *command.DockerCli, services map[string]swarm.ServiceSpec) error {
+	apiclient := dockerCli.Client()
+
+	var missing []string
+	for internalName, serviceSpec := range services {
+		containerSpec := serviceSpec.TaskTemplate.ContainerSpec
+
+		secrets := []*swarm.SecretReference{}
+		for _, reference := range containerSpec.Secrets {
+			secret, _, err := apiclient.SecretInspectWithRaw(ctx, reference.SecretName)
+			if client.IsErrSecretNotFound(err) {
+				missing = append(missing, fmt.Sprintf("secret %s for service %s", reference.SecretName, internalName))
+				continue
+			} else if err != nil {
+				return err
+			}
+			relinked := *reference
+			relinked.SecretID = secret.ID
+			secrets = append(secrets, &relinked)
+		}
+		containerSpec.Secrets = secrets
+
+		configs := []*swarm.ConfigReference{}
+		for _, reference := range containerSpec.Configs {
+			config, _, err := apiclient.ConfigInspectWithRaw(ctx, reference.ConfigName)
+			if client.IsErrNotFound(err) {
+				missing = append(missing, fmt.Sprintf("config %s for service %s", reference.ConfigName, internalName))
+				continue
+			} else if err != nil {
+				return err
+			}
+			relinked := *reference
+			relinked.ConfigID = config.ID
+			configs = append(configs, &relinked)
+		}
+		containerSpec.Configs = configs
+
+		serviceSpec.TaskTemplate.ContainerSpec = containerSpec
+		services[internalName] = serviceSpec
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("Cannot redeploy, the snapshot uses objects that no longer exist: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+/**
+ * Snapshot diffs
+ */
+
+// DiffSnapshots compares two snapshots section by section (config, and each
+// network, service and image) and returns diff lines for changed sections.
+func DiffSnapshots(from, to *Snapshot) []string {
+	fromSections := snapshotSections(from)
+	toSections := snapshotSections(to)
+
+	keys := map[string]struct{}{}
+	for key := range fromSections {
+		keys[key] = struct{}{}
+	}
+	for key := range toSections {
+		keys[key] = struct{}{}
+	}
+	var sorted []string
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var lines []string
+	for _, key := range sorted {
+		a, b := fromSections[key], toSections[key]
+		if strings.Join(a, "\n") == strings.Join(b, "\n") {
+			continue
+		}
+		lines = append(lines, "@@ "+key+" @@")
+		lines = append(lines, diffLines(a, b)...)
+	}
+	return lines
+}
+
+func snapshotSections(snapshot *Snapshot) map[string][]string {
+	sections := map[string][]string{
+		"config": jsonLines(snapshot.Config),
+	}
+	for name, network := range snapshot.Networks {
+		sections["network "+name] = jsonLines(network)
+	}
+	for name, service := range snapshot.Services {
+		sections["service "+name] = jsonLines(service)
+	}
+	for name, image := range snapshot.Images {
+		sections["image "+name] = []string{image}
+	}
+	return sections
+}
+
+func jsonLines(value interface{}) []string {
+	bytes, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return []string{err.Error()}
+	}
+	return strings.Split(string(bytes), "\n")
+}
+
+// diffLines produces a line diff based on the longest common subsequence,
+// keeping only a few lines of context around each change.
+func diffLines(a, b []string) []string {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var full []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			full = append(full, "  "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			full = append(full, "- "+a[i])
+			i++
+		default:
+			full = append(full, "+ "+b[j])
+			j++
+		}
+	}
+
+	// only keep lines near a change
+	keep := make([]bool, len(full))
+	for index, line := range full {
+		if strings.HasPrefix(line, "  ") {
+			continue
+		}
+		for k := index - snapshotDiffContext; k <= index+snapshotDiffContext; k++ {
+			if k >= 0 && k < len(full) {
+				keep[k] = true
+			}
+		}
+	}
+
+	var lines []string
+	skipped := false
+	for index, line := range full {
+		if !keep[index] {
+			skipped = true
+			continue
+		}
+		if skipped && len(lines) > 0 {
+			lines = append(lines, "  ...")
+		}
+		skipped = false
+		lines = append(lines, line)
+	}
+	return lines
+}
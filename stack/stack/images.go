@@ -0,0 +1,306 @@
+package stack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/cli/compose/convert"
+	"github.com/docker/docker/cli/compose/loader"
+	composetypes "github.com/docker/docker/cli/compose/types"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+const (
+	// By default pull or push this many images at once, as the engine does layers
+	defaultImageParallelism = 3
+)
+
+/**
+ * Stack images
+ *
+ * The images of a stack are collected from the service specs that a deploy
+ * would create, and then pulled to the engine the client is connected to, or
+ * pushed to their registries.
+ */
+
+type ImagesOptions struct {
+	composefile  string
+	namespace    string
+	parallelism  int
+	registryAuth RegistryAuthResolver
+	events       func(ImageEvent)
+}
+
+func New_ImagesOptions(composefile string, namespace string) *ImagesOptions {
+	return &ImagesOptions{
+		composefile: composefile,
+		namespace:   namespace,
+		parallelism: defaultImageParallelism,
+	}
+}
+
+// Pull or push this many images at the same time
+func (opts *ImagesOptions) SetParallelism(parallelism int) {
+	opts.parallelism = parallelism
+}
+
+// Use a resolver for registry auth, instead of the docker cli config
+func (opts *ImagesOptions) SetRegistryAuthResolver(resolver RegistryAuthResolver) {
+	opts.registryAuth = resolver
+}
+
+// Receive progress events, instead of writing progress to the cli output.  The
+// handler is called concurrently from up to parallelism goroutines, one for
+// each image being transferred, so it must be safe for concurrent use.
+func (opts *ImagesOptions) SetEvents(events func(ImageEvent)) {
+	opts.events = events
+}
+
+// ImageEvent is a progress update for a single image
+type ImageEvent struct {
+	Image  string
+	Action string
+	// ID is the layer that the status is about, if any
+	ID      string
+	Status  string
+	Current int64
+	Total   int64
+	Error   string
+}
+
+// ImageResult is the outcome of pulling or pushing a single image
+type ImageResult struct {
+	Image string
+	// Services are the stack services that use the image
+	Services []string
+	Action   string
+	Error    error
+}
+
+// ImageResults holds the outcomes for all stack images
+type ImageResults []ImageResult
+
+func (results ImageResults) Len() int           { return len(results) }
+func (results ImageResults) Swap(i, j int)      { results[i], results[j] = results[j], results[i] }
+func (results ImageResults) Less(i, j int) bool { return results[i].Image < results[j].Image }
+
+// Failed returns only those results which carry an error
+func (results ImageResults) Failed() ImageResults {
+	failed := ImageResults{}
+	for _, result := range results {
+		if result.Error != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Err aggregates all of the image errors into a single error, or nil if
+// every image succeeded
+func (results ImageResults) Err() error {
+	failed := results.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	var msgs []string
+	for _, result := range failed {
+		msgs = append(msgs, fmt.Sprintf("%s (%s): %s", result.Image, strings.Join(result.Services, ", "), result.Error))
+	}
+	return fmt.Errorf("Failed for %d of %d images:\n%s", len(failed), len(results), strings.Join(msgs, "\n"))
+}
+
+// RunPull pulls all stack images to the engine that the client is connected to
+func RunPull(dockerCli *command.DockerCli, opts ImagesOptions) (ImageResults, error) {
+	return runImages(dockerCli, opts, "pull", func(ctx context.Context, image, encodedAuth string) (io.ReadCloser, error) {
+		return dockerCli.Client().ImagePull(ctx, image, types.ImagePullOptions{RegistryAuth: encodedAuth})
+	})
+}
+
+// RunPush pushes all stack images to their registries
+func RunPush(dockerCli *command.DockerCli, opts ImagesOptions) (ImageResults, error) {
+	return runImages(dockerCli, opts, "push", func(ctx context.Context, image, encodedAuth string) (io.ReadCloser, error) {
+		return dockerCli.Client().ImagePush(ctx, image, types.ImagePushOptions{RegistryAuth: encodedAuth})
+	})
+}
+
+func runImages(
+	dockerCli *command.DockerCli,
+	opts ImagesOptions,
+	action string,
+	transfer func(ctx context.Context, image, encodedAuth string) (io.ReadCloser, error),
+) (ImageResults, error) {
+	ctx := context.Background()
+
+	services, err := stackServiceSpecs(ctx, dockerCli, opts.composefile, opts.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	users := map[string][]string{}
+	for name, spec := range services {
+		image := spec.TaskTemplate.ContainerSpec.Image
+		users[image] = append(users[image], name)
+	}
+
+	results := ImageResults{}
+	for image, names := range users {
+		sort.Strings(names)
+		results = append(results, ImageResult{Image: image, Services: names, Action: action})
+	}
+	sort.Sort(results)
+
+	resolver := opts.registryAuth
+	if resolver == nil {
+		resolver = New_CliRegistryAuthResolver()
+	}
+
+	events := opts.events
+	if events == nil {
+		events = printImageEvent(dockerCli)
+	}
+
+	parallelism := opts.parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	// a pool of workers takes the images in order
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < parallelism; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				result := &results[index]
+				result.Error = transferImage(ctx, dockerCli, result.Image, action, resolver, transfer, events)
+			}
+		}()
+	}
+	for index := range results {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	failed := results.Failed()
+	fmt.Fprintf(dockerCli.Out(), "%s: %d images, %d failed\n", strings.Title(action), len(results), len(failed))
+	return results, results.Err()
+}
+
+// transferImage pulls or pushes one image, turning the engine progress
+// stream into events
+func transferImage(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	image string,
+	action string,
+	resolver RegistryAuthResolver,
+	transfer func(ctx context.Context, image, encodedAuth string) (io.ReadCloser, error),
+	events func(ImageEvent),
+) error {
+	encodedAuth, err := resolver.EncodedRegistryAuth(ctx, dockerCli, image)
+	if err != nil {
+		return err
+	}
+
+	responseBody, err := transfer(ctx, image, encodedAuth)
+	if err != nil {
+		events(ImageEvent{Image: image, Action: action, Error: err.Error()})
+		return err
+	}
+	defer responseBody.Close()
+
+	decoder := json.NewDecoder(responseBody)
+	for {
+		var message jsonmessage.JSONMessage
+		if err := decoder.Decode(&message); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		event := ImageEvent{
+			Image:  image,
+			Action: action,
+			ID:     message.ID,
+			Status: message.Status,
+		}
+		if message.Progress != nil {
+			event.Current = message.Progress.Current
+			event.Total = message.Progress.Total
+		}
+		if message.Error != nil {
+			event.Error = message.Error.Message
+		}
+		events(event)
+
+		if message.Error != nil {
+			return message.Error
+		}
+	}
+}
+
+// printImageEvent writes events without progress counts to the cli output,
+// so that parallel transfers stay readable
+func printImageEvent(dockerCli *command.DockerCli) func(ImageEvent) {
+	var lock sync.Mutex
+	return func(event ImageEvent) {
+		if event.Total > 0 {
+			return
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		switch {
+		case event.Error != "":
+			fmt.Fprintf(dockerCli.Err(), "%s: %s\n", event.Image, event.Error)
+		case event.ID != "":
+			fmt.Fprintf(dockerCli.Out(), "%s: %s %s\n", event.Image, event.ID, event.Status)
+		default:
+			fmt.Fprintf(dockerCli.Out(), "%s: %s\n", event.Image, event.Status)
+		}
+	}
+}
+
+// stackServiceSpecs converts a compose file to the service specs that a
+// deploy would create, without creating anything.  Secrets are left out, as
+// they may not exist yet.
+func stackServiceSpecs(ctx context.Context, dockerCli *command.DockerCli, composefile, namespace string) (map[string]swarm.ServiceSpec, error) {
+	config, err := loadStackConfig(composefile, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for index, service := range config.Services {
+		service.Secrets = nil
+		config.Services[index] = service
+	}
+
+	return convert.Services(convert.NewNamespace(namespace), config, dockerCli.Client())
+}
+
+// loadStackConfig loads a compose file the way a deploy does, taking out the
+// sections that the loader does not support
+func loadStackConfig(composefile, namespace string) (*composetypes.Config, error) {
+	configDetails, err := getConfigDetails(DeployOptions{composefile: composefile})
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := extractComposeConfigs(&configDetails); err != nil {
+		return nil, err
+	}
+	if _, err := extractComposeBuilds(&configDetails, convert.NewNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	return loader.Load(configDetails)
+}
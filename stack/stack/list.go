@@ -4,33 +4,77 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path"
 	"sort"
 	"strconv"
 	"text/tabwriter"
+	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/cli/command"
 	"github.com/docker/docker/cli/compose/convert"
 	"github.com/docker/docker/client"
 )
 
 const (
-	listItemFmt = "%s\t%s\n"
+	listItemFmt = "%s\t%s\t%s\t%s\t%s\t%s\t%s\n"
 )
 
 type ListOptions struct {
+	format      string
+	namePattern string
+	labels      []string
+}
+
+func New_ListOptions() *ListOptions {
+	return &ListOptions{
+		format: FormatTable,
+	}
+}
+
+// Write the stacks as a table, json or yaml
+func (opts *ListOptions) SetFormat(format string) {
+	opts.format = format
+}
+
+// Only list stacks whose name matches a shell pattern, such as project-*
+func (opts *ListOptions) SetNamePattern(pattern string) {
+	opts.namePattern = pattern
+}
+
+// Only list stacks with services that have these labels, as key or key=value
+func (opts *ListOptions) SetLabels(labels []string) {
+	opts.labels = labels
+}
+
+// stackList is the json and yaml schema of the stack list
+type stackList struct {
+	Stacks []*stack `json:"stacks" yaml:"stacks"`
 }
 
 func RunList(dockerCli *command.DockerCli, opts ListOptions) error {
 	client := dockerCli.Client()
 	ctx := context.Background()
 
-	stacks, err := getStacks(ctx, client)
+	if opts.namePattern != "" {
+		// check the pattern before listing anything
+		if _, err := path.Match(opts.namePattern, ""); err != nil {
+			return fmt.Errorf("Invalid stack name pattern %q: %s", opts.namePattern, err)
+		}
+	}
+
+	stacks, err := getStacks(ctx, client, opts)
 	if err != nil {
 		return err
 	}
 
 	out := dockerCli.Out()
+	if isStructuredFormat(opts.format) {
+		sort.Sort(byName(stacks))
+		return writeStructured(out, opts.format, stackList{Stacks: stacks})
+	}
 	printTable(out, stacks)
 	return nil
 }
@@ -49,35 +93,63 @@ func printTable(out io.Writer, stacks []*stack) {
 
 	sort.Sort(byName(stacks))
 
-	fmt.Fprintf(writer, listItemFmt, "NAME", "SERVICES")
+	fmt.Fprintf(writer, listItemFmt, "NAME", "SERVICES", "NETWORKS", "SECRETS", "CONFIGS", "TASKS", "UPDATED")
 	for _, stack := range stacks {
+		updated := "-"
+		if !stack.UpdatedAt.IsZero() {
+			updated = stack.UpdatedAt.Local().Format("2006-01-02 15:04:05")
+		}
 		fmt.Fprintf(
 			writer,
 			listItemFmt,
 			stack.Name,
 			strconv.Itoa(stack.Services),
+			strconv.Itoa(len(stack.Networks)),
+			strconv.Itoa(len(stack.Secrets)),
+			strconv.Itoa(len(stack.Configs)),
+			fmt.Sprintf("%d/%d", stack.Running, stack.Desired),
+			updated,
 		)
 	}
 }
 
 type stack struct {
 	// Name is the name of the stack
-	Name string
+	Name string `json:"name" yaml:"name"`
 	// Services is the number of the services
-	Services int
+	Services int `json:"services" yaml:"services"`
+	// ServiceIDs are the swarm IDs of the services
+	ServiceIDs []string `json:"service_ids" yaml:"service_ids"`
+	// Networks, Secrets and Configs are the names of the objects created by
+	// the stack, without the external objects that it uses
+	Networks []string `json:"networks" yaml:"networks"`
+	Secrets  []string `json:"secrets" yaml:"secrets"`
+	Configs  []string `json:"configs" yaml:"configs"`
+	// Desired and Running count the tasks of all services
+	Desired uint64 `json:"desired" yaml:"desired"`
+	Running uint64 `json:"running" yaml:"running"`
+	// UpdatedAt is the last time that a service of the stack was changed
+	UpdatedAt time.Time `json:"updated_at" yaml:"updated_at"`
 }
 
 func getStacks(
 	ctx context.Context,
 	apiclient client.APIClient,
+	opts ListOptions,
 ) ([]*stack, error) {
+	filter := getAllStacksFilter()
+	for _, label := range opts.labels {
+		filter.Add("label", label)
+	}
+
 	services, err := apiclient.ServiceList(
 		ctx,
-		types.ServiceListOptions{Filters: getAllStacksFilter()})
+		types.ServiceListOptions{Filters: filter})
 	if err != nil {
 		return nil, err
 	}
 	m := make(map[string]*stack, 0)
+	serviceStacks := map[string]*stack{}
 	for _, service := range services {
 		labels := service.Spec.Labels
 		name, ok := labels[convert.LabelNamespace]
@@ -85,19 +157,135 @@ func getStacks(
 			return nil, fmt.Errorf("cannot get label %s for service %s",
 				convert.LabelNamespace, service.ID)
 		}
+		if opts.namePattern != "" {
+			if matched, _ := path.Match(opts.namePattern, name); !matched {
+				continue
+			}
+		}
 		ztack, ok := m[name]
 		if !ok {
-			m[name] = &stack{
+			ztack = &stack{
 				Name:     name,
-				Services: 1,
+				Networks: []string{},
+				Secrets:  []string{},
+				Configs:  []string{},
 			}
-		} else {
-			ztack.Services++
+			m[name] = ztack
+		}
+		ztack.Services++
+		ztack.ServiceIDs = append(ztack.ServiceIDs, service.ID)
+		if service.Meta.UpdatedAt.After(ztack.UpdatedAt) {
+			ztack.UpdatedAt = service.Meta.UpdatedAt
 		}
+		if service.Spec.Mode.Replicated != nil && service.Spec.Mode.Replicated.Replicas != nil {
+			ztack.Desired += *service.Spec.Mode.Replicated.Replicas
+		}
+		serviceStacks[service.ID] = ztack
 	}
-	var stacks []*stack
+
+	stacks := []*stack{}
 	for _, stack := range m {
+		sort.Strings(stack.ServiceIDs)
 		stacks = append(stacks, stack)
 	}
+	if len(stacks) == 0 {
+		return stacks, nil
+	}
+
+	if err := countStackTasks(ctx, apiclient, services, serviceStacks); err != nil {
+		return nil, err
+	}
+	if err := addStackObjects(ctx, apiclient, m); err != nil {
+		return nil, err
+	}
 	return stacks, nil
 }
+
+// countStackTasks counts the running tasks of the listed stacks.  Global
+// services want a task on each of their nodes, so the tasks that swarm wants
+// running are counted as desired for them.
+func countStackTasks(
+	ctx context.Context,
+	apiclient client.APIClient,
+	services []swarm.Service,
+	serviceStacks map[string]*stack,
+) error {
+	taskFilter := filters.NewArgs()
+	global := map[string]bool{}
+	for _, service := range services {
+		if _, listed := serviceStacks[service.ID]; !listed {
+			continue
+		}
+		taskFilter.Add("service", service.ID)
+		global[service.ID] = service.Spec.Mode.Global != nil
+	}
+
+	tasks, err := apiclient.TaskList(ctx, types.TaskListOptions{Filters: taskFilter})
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		ztack, listed := serviceStacks[task.ServiceID]
+		if !listed || task.DesiredState != swarm.TaskStateRunning {
+			continue
+		}
+		if global[task.ServiceID] {
+			ztack.Desired++
+		}
+		if task.Status.State == swarm.TaskStateRunning {
+			ztack.Running++
+		}
+	}
+	return nil
+}
+
+// addStackObjects adds the names of the networks, secrets and configs of the
+// listed stacks.  Objects are found by the stack namespace label, so external
+// objects that a stack uses but did not create are not listed.
+func addStackObjects(ctx context.Context, apiclient client.APIClient, stacks map[string]*stack) error {
+	filter := getAllStacksFilter()
+
+	networks, err := apiclient.NetworkList(ctx, types.NetworkListOptions{Filters: filter})
+	if err != nil {
+		return err
+	}
+	for _, network := range networks {
+		if ztack, listed := stacks[network.Labels[convert.LabelNamespace]]; listed {
+			ztack.Networks = append(ztack.Networks, network.Name)
+		}
+	}
+
+	secrets, err := apiclient.SecretList(ctx, types.SecretListOptions{Filters: filter})
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		if ztack, listed := stacks[secret.Spec.Labels[convert.LabelNamespace]]; listed {
+			ztack.Secrets = append(ztack.Secrets, secret.Spec.Name)
+		}
+	}
+
+	// daemons before API 1.30 have no configs, so their stacks have none
+	supported, err := configsSupported(ctx, apiclient)
+	if err != nil {
+		return err
+	}
+	if supported {
+		configs, err := apiclient.ConfigList(ctx, types.ConfigListOptions{Filters: filter})
+		if err != nil {
+			return err
+		}
+		for _, config := range configs {
+			if ztack, listed := stacks[config.Spec.Labels[convert.LabelNamespace]]; listed {
+				ztack.Configs = append(ztack.Configs, config.Spec.Name)
+			}
+		}
+	}
+
+	for _, ztack := range stacks {
+		sort.Strings(ztack.Networks)
+		sort.Strings(ztack.Secrets)
+		sort.Strings(ztack.Configs)
+	}
+	return nil
+}
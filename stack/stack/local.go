@@ -0,0 +1,633 @@
+package stack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/swarm"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/cli/compose/convert"
+	"github.com/docker/docker/cli/compose/loader"
+	composetypes "github.com/docker/docker/cli/compose/types"
+	apiclient "github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+const (
+	// Deploy stacks as swarm services
+	DeployModeSwarm = "swarm"
+	// Deploy stacks as plain containers on a single engine, without swarm
+	DeployModeLocal = "local"
+
+	// Labels that docker-compose puts on containers, so that compose tools
+	// recognize the containers of a local stack
+	labelComposeProject         = "com.docker.compose.project"
+	labelComposeService         = "com.docker.compose.service"
+	labelComposeContainerNumber = "com.docker.compose.container-number"
+	labelComposeOneoff          = "com.docker.compose.oneoff"
+	labelComposeConfigHash      = "com.docker.compose.config-hash"
+
+	// Network driver for local stacks, as overlay networks need a swarm
+	defaultLocalNetworkDriver = "bridge"
+	// Path that secret files are mounted under, as swarm does
+	localSecretsPath = "/run/secrets"
+)
+
+/**
+ * Local stacks
+ *
+ * In local mode a compose file is converted to swarm service specs as usual,
+ * and each service spec is then run as one or more plain containers, with
+ * bridge networks and named volumes.  Secrets are bind mounted from their
+ * files, as there is no swarm to store them.
+ */
+
+// deployLocal deploys a compose file as containers on a single engine
+func deployLocal(ctx context.Context, dockerCli *command.DockerCli, opts DeployOptions) (*DeployResult, error) {
+	if opts.bundlefile != "" {
+		return nil, fmt.Errorf("Bundle files can only be deployed to a swarm, use a Compose file for local stacks.")
+	}
+
+	configDetails, err := getConfigDetails(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	composeConfigs, _, err := extractComposeConfigs(&configDetails)
+	if err != nil {
+		return nil, err
+	}
+	if err := deployBuilds(ctx, dockerCli, &configDetails, opts); err != nil {
+		return nil, err
+	}
+	if len(composeConfigs) > 0 {
+		fmt.Fprintf(dockerCli.Err(), "Ignoring configs, which need a swarm\n")
+	}
+
+	config, err := loader.Load(configDetails)
+	if err != nil {
+		if fpe, ok := err.(*loader.ForbiddenPropertiesError); ok {
+			return nil, fmt.Errorf("Compose file contains unsupported options:\n\n%s\n",
+				propertyWarnings(fpe.Properties))
+		}
+
+		return nil, err
+	}
+
+	namespace := convert.NewNamespace(opts.namespace)
+
+	serviceNetworks := getServicesDeclaredNetworks(config.Services)
+
+	networks, externalNetworks := convert.Networks(namespace, config.Networks, serviceNetworks)
+	if err := validateExternalNetworks(ctx, dockerCli, externalNetworks); err != nil {
+		return nil, err
+	}
+	for name, createOpts := range networks {
+		if createOpts.Driver == "" || createOpts.Driver == defaultNetworkDriver {
+			createOpts.Driver = defaultLocalNetworkDriver
+			createOpts.Attachable = false
+			networks[name] = createOpts
+		}
+	}
+	if err := createNetworks(ctx, dockerCli, namespace, networks); err != nil {
+		return nil, err
+	}
+
+	if err := createLocalVolumes(ctx, dockerCli, namespace, config.Volumes); err != nil {
+		return nil, err
+	}
+
+	// secrets become bind mounts, so they are kept out of the service conversion
+	secretMounts, err := localSecretMounts(configDetails.WorkingDir, config)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := convert.Services(namespace, config, dockerCli.Client())
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := ServiceResults{}
+	for _, name := range names {
+		result := deployLocalService(ctx, dockerCli, namespace, name, services[name], secretMounts[name], opts)
+		if result.Error != nil {
+			fmt.Fprintf(dockerCli.Err(), "Failed to deploy service %s: %s\n", result.Name, result.Error)
+		}
+		results = append(results, result)
+	}
+
+	if err := results.Err(); err != nil {
+		return &DeployResult{Services: results}, err
+	}
+
+	orphans, err := removeLocalOrphans(ctx, dockerCli, namespace, services)
+	results = append(results, orphans...)
+	if err != nil {
+		return &DeployResult{Services: results}, err
+	}
+	return &DeployResult{
+		Services: results,
+		Images:   map[string]string{},
+		snapshot: newSnapshot(opts, config, networks, services),
+	}, nil
+}
+
+// createLocalVolumes creates the named stack volumes, with the stack label,
+// so that they can be found when the stack is removed
+func createLocalVolumes(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	namespace convert.Namespace,
+	volumes map[string]composetypes.VolumeConfig,
+) error {
+	client := dockerCli.Client()
+
+	for internalName, volume := range volumes {
+		if volume.External.External {
+			continue
+		}
+
+		name := namespace.Scope(internalName)
+		if _, err := client.VolumeInspect(ctx, name); err == nil {
+			continue
+		} else if !apiclient.IsErrNotFound(err) {
+			return err
+		}
+
+		fmt.Fprintf(dockerCli.Out(), "Creating volume %s\n", name)
+		if _, err := client.VolumeCreate(ctx, volumetypes.VolumesCreateBody{
+			Name:       name,
+			Driver:     volume.Driver,
+			DriverOpts: volume.DriverOpts,
+			Labels:     convert.AddStackLabel(namespace, volume.Labels),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// localSecretMounts removes the secrets from the compose services, and
+// returns read only bind mounts of the secret files instead
+func localSecretMounts(workingDir string, config *composetypes.Config) (map[string][]mount.Mount, error) {
+	mounts := map[string][]mount.Mount{}
+
+	for index, service := range config.Services {
+		for _, secret := range service.Secrets {
+			secretConfig, exists := config.Secrets[secret.Source]
+			if !exists {
+				return nil, fmt.Errorf("service %s uses undefined secret %s", service.Name, secret.Source)
+			}
+			if secretConfig.External.External {
+				return nil, fmt.Errorf("service %s uses external secret %s, which needs a swarm", service.Name, secret.Source)
+			}
+
+			file := secretConfig.File
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(workingDir, file)
+			}
+			target := secret.Target
+			if target == "" {
+				target = secret.Source
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(localSecretsPath, target)
+			}
+
+			mounts[service.Name] = append(mounts[service.Name], mount.Mount{
+				Type:     mount.TypeBind,
+				Source:   file,
+				Target:   target,
+				ReadOnly: true,
+			})
+		}
+
+		service.Secrets = nil
+		config.Services[index] = service
+	}
+	return mounts, nil
+}
+
+// deployLocalService runs the containers of a service, replacing those whose
+// configuration changed, and removing those above the replica count
+func deployLocalService(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	namespace convert.Namespace,
+	name string,
+	spec swarm.ServiceSpec,
+	secretMounts []mount.Mount,
+	opts DeployOptions,
+) ServiceResult {
+	client := dockerCli.Client()
+	result := ServiceResult{Name: spec.Name, Action: "unchanged"}
+
+	replicas := 1
+	if spec.Mode.Replicated != nil && spec.Mode.Replicated.Replicas != nil {
+		replicas = int(*spec.Mode.Replicated.Replicas)
+	}
+
+	containerFilter := getStackFilter(namespace.Name())
+	containerFilter.Add("label", labelComposeService+"="+name)
+	existing, err := client.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: containerFilter})
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	existingByNumber := map[int]types.Container{}
+	for _, existingContainer := range existing {
+		number, _ := strconv.Atoi(existingContainer.Labels[labelComposeContainerNumber])
+		if number > replicas || number < 1 {
+			fmt.Fprintf(dockerCli.Out(), "Removing container %s\n", strings.TrimPrefix(existingContainer.Names[0], "/"))
+			if err := client.ContainerRemove(ctx, existingContainer.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+				result.Error = err
+				return result
+			}
+			result.Action = "update"
+			continue
+		}
+		existingByNumber[number] = existingContainer
+	}
+
+	if err := ensureLocalImage(ctx, dockerCli, spec.TaskTemplate.ContainerSpec.Image, opts); err != nil {
+		result.Error = err
+		return result
+	}
+
+	for number := 1; number <= replicas; number++ {
+		containerName := fmt.Sprintf("%s_%d", spec.Name, number)
+		config, hostConfig, networks, err := localContainerConfig(namespace, name, number, spec, secretMounts)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		if number > 1 && len(hostConfig.PortBindings) > 0 {
+			// published ports can only be bound by one container
+			fmt.Fprintf(dockerCli.Err(), "Only publishing the ports of service %s on its first container\n", spec.Name)
+			hostConfig.PortBindings = nil
+		}
+		hash := localConfigHash(config, hostConfig, networks)
+		config.Labels[labelComposeConfigHash] = hash
+
+		if current, exists := existingByNumber[number]; exists {
+			if current.Labels[labelComposeConfigHash] == hash {
+				if current.State != "running" {
+					fmt.Fprintf(dockerCli.Out(), "Starting container %s\n", containerName)
+					if err := client.ContainerStart(ctx, current.ID, types.ContainerStartOptions{}); err != nil {
+						result.Error = err
+						return result
+					}
+				}
+				continue
+			}
+
+			fmt.Fprintf(dockerCli.Out(), "Recreating container %s\n", containerName)
+			if err := client.ContainerRemove(ctx, current.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+				result.Error = err
+				return result
+			}
+			result.Action = "update"
+		} else {
+			fmt.Fprintf(dockerCli.Out(), "Creating container %s\n", containerName)
+			if result.Action == "unchanged" {
+				result.Action = "create"
+			}
+		}
+
+		if err := createLocalContainer(ctx, dockerCli, containerName, config, hostConfig, networks); err != nil {
+			result.Error = err
+			return result
+		}
+	}
+	return result
+}
+
+// createLocalContainer creates and starts a container, attached to the first
+// network on creation and to the others before it is started
+func createLocalContainer(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	name string,
+	config *container.Config,
+	hostConfig *container.HostConfig,
+	networks []swarm.NetworkAttachmentConfig,
+) error {
+	client := dockerCli.Client()
+
+	networkingConfig := &network.NetworkingConfig{EndpointsConfig: map[string]*network.EndpointSettings{}}
+	if len(networks) > 0 {
+		hostConfig.NetworkMode = container.NetworkMode(networks[0].Target)
+		networkingConfig.EndpointsConfig[networks[0].Target] = &network.EndpointSettings{Aliases: networks[0].Aliases}
+	}
+
+	response, err := client.ContainerCreate(ctx, config, hostConfig, networkingConfig, name)
+	if err != nil {
+		return err
+	}
+	for _, warning := range response.Warnings {
+		fmt.Fprintln(dockerCli.Err(), warning)
+	}
+
+	for index, attachment := range networks {
+		if index == 0 {
+			continue
+		}
+		if err := client.NetworkConnect(ctx, attachment.Target, response.ID, &network.EndpointSettings{Aliases: attachment.Aliases}); err != nil {
+			return err
+		}
+	}
+
+	return client.ContainerStart(ctx, response.ID, types.ContainerStartOptions{})
+}
+
+// removeLocalOrphans removes the stack containers of services that are no
+// longer in the compose file
+func removeLocalOrphans(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	namespace convert.Namespace,
+	services map[string]swarm.ServiceSpec,
+) (ServiceResults, error) {
+	client := dockerCli.Client()
+
+	containers, err := client.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: getStackFilter(namespace.Name())})
+	if err != nil {
+		return nil, err
+	}
+
+	orphans := map[string][]types.Container{}
+	for _, stackContainer := range containers {
+		name := stackContainer.Labels[labelComposeService]
+		if _, exists := services[name]; !exists {
+			orphans[name] = append(orphans[name], stackContainer)
+		}
+	}
+
+	var names []string
+	for name := range orphans {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := ServiceResults{}
+	for _, name := range names {
+		fmt.Fprintf(dockerCli.Out(), "Removing containers of service %s, which is no longer in the stack\n", namespace.Scope(name))
+		result := ServiceResult{Name: namespace.Scope(name), Action: "remove"}
+		if removeLocalContainers(ctx, dockerCli, orphans[name]) {
+			result.Error = fmt.Errorf("Failed to remove some containers of service %s", result.Name)
+		}
+		results = append(results, result)
+	}
+	return results, results.Err()
+}
+
+// localContainerConfig converts a service spec to the configuration of one
+// of its containers
+func localContainerConfig(
+	namespace convert.Namespace,
+	name string,
+	number int,
+	spec swarm.ServiceSpec,
+	secretMounts []mount.Mount,
+) (*container.Config, *container.HostConfig, []swarm.NetworkAttachmentConfig, error) {
+	containerSpec := spec.TaskTemplate.ContainerSpec
+
+	labels := copyLabels(containerSpec.Labels)
+	labels[convert.LabelNamespace] = namespace.Name()
+	labels[labelComposeProject] = namespace.Name()
+	labels[labelComposeService] = name
+	labels[labelComposeContainerNumber] = strconv.Itoa(number)
+	labels[labelComposeOneoff] = "False"
+
+	config := &container.Config{
+		Image:       containerSpec.Image,
+		Entrypoint:  containerSpec.Command,
+		Cmd:         containerSpec.Args,
+		Env:         containerSpec.Env,
+		User:        containerSpec.User,
+		WorkingDir:  containerSpec.Dir,
+		Hostname:    containerSpec.Hostname,
+		Tty:         containerSpec.TTY,
+		OpenStdin:   containerSpec.OpenStdin,
+		Labels:      labels,
+		Healthcheck: containerSpec.Healthcheck,
+	}
+	if containerSpec.StopGracePeriod != nil {
+		timeout := int(containerSpec.StopGracePeriod.Seconds())
+		config.StopTimeout = &timeout
+	}
+
+	hostConfig := &container.HostConfig{
+		Mounts:     append(append([]mount.Mount{}, containerSpec.Mounts...), secretMounts...),
+		ExtraHosts: localExtraHosts(containerSpec.Hosts),
+	}
+	if containerSpec.DNSConfig != nil {
+		hostConfig.DNS = containerSpec.DNSConfig.Nameservers
+		hostConfig.DNSSearch = containerSpec.DNSConfig.Search
+		hostConfig.DNSOptions = containerSpec.DNSConfig.Options
+	}
+	if resources := spec.TaskTemplate.Resources; resources != nil && resources.Limits != nil {
+		hostConfig.Resources.NanoCPUs = resources.Limits.NanoCPUs
+		hostConfig.Resources.Memory = resources.Limits.MemoryBytes
+	}
+	hostConfig.RestartPolicy = localRestartPolicy(spec.TaskTemplate.RestartPolicy)
+
+	if err := localPorts(spec, config, hostConfig); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// the service name is also resolvable on each network, as in swarm
+	var networks []swarm.NetworkAttachmentConfig
+	for _, attachment := range spec.Networks {
+		attachment.Aliases = append([]string{name}, attachment.Aliases...)
+		networks = append(networks, attachment)
+	}
+
+	return config, hostConfig, networks, nil
+}
+
+// localPortBinding has the JSON form of a docker port binding
+type localPortBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+// localPorts exposes and publishes the service ports on a container.  The
+// port maps are types of the go-connections package vendored inside docker,
+// so they are filled through their JSON form rather than by name.
+func localPorts(spec swarm.ServiceSpec, config *container.Config, hostConfig *container.HostConfig) error {
+	if spec.EndpointSpec == nil || len(spec.EndpointSpec.Ports) == 0 {
+		return nil
+	}
+
+	exposedPorts := map[string]struct{}{}
+	portBindings := map[string][]localPortBinding{}
+	for _, portConfig := range spec.EndpointSpec.Ports {
+		port := fmt.Sprintf("%d/%s", portConfig.TargetPort, portConfig.Protocol)
+		exposedPorts[port] = struct{}{}
+		if portConfig.PublishedPort > 0 {
+			portBindings[port] = append(portBindings[port], localPortBinding{
+				HostPort: strconv.FormatUint(uint64(portConfig.PublishedPort), 10),
+			})
+		}
+	}
+
+	if err := assignJSON(&config.ExposedPorts, exposedPorts); err != nil {
+		return err
+	}
+	return assignJSON(&hostConfig.PortBindings, portBindings)
+}
+
+// assignJSON sets target to value by converting value through JSON
+func assignJSON(target interface{}, value interface{}) error {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, target)
+}
+
+// localExtraHosts converts swarm "IP host" entries to "host:IP" extra hosts
+func localExtraHosts(hosts []string) []string {
+	var extraHosts []string
+	for _, host := range hosts {
+		if parts := strings.Fields(host); len(parts) > 1 {
+			for _, hostname := range parts[1:] {
+				extraHosts = append(extraHosts, hostname+":"+parts[0])
+			}
+		}
+	}
+	return extraHosts
+}
+
+// localRestartPolicy maps a swarm restart policy to a container one
+func localRestartPolicy(policy *swarm.RestartPolicy) container.RestartPolicy {
+	if policy == nil {
+		return container.RestartPolicy{Name: "no"}
+	}
+	switch policy.Condition {
+	case swarm.RestartPolicyConditionNone:
+		return container.RestartPolicy{Name: "no"}
+	case swarm.RestartPolicyConditionOnFailure:
+		restartPolicy := container.RestartPolicy{Name: "on-failure"}
+		if policy.MaxAttempts != nil {
+			restartPolicy.MaximumRetryCount = int(*policy.MaxAttempts)
+		}
+		return restartPolicy
+	default:
+		return container.RestartPolicy{Name: "unless-stopped"}
+	}
+}
+
+// localConfigHash hashes the container configuration, so that unchanged
+// containers are left running
+func localConfigHash(config *container.Config, hostConfig *container.HostConfig, networks []swarm.NetworkAttachmentConfig) string {
+	encoded, _ := json.Marshal(struct {
+		Config     *container.Config
+		HostConfig *container.HostConfig
+		Networks   []swarm.NetworkAttachmentConfig
+	}{config, hostConfig, networks})
+	hash := sha256.Sum256(encoded)
+	return hex.EncodeToString(hash[:])
+}
+
+// ensureLocalImage pulls a service image if the engine does not have it
+func ensureLocalImage(ctx context.Context, dockerCli *command.DockerCli, image string, opts DeployOptions) error {
+	client := dockerCli.Client()
+
+	if _, _, err := client.ImageInspectWithRaw(ctx, image); err == nil {
+		return nil
+	} else if !apiclient.IsErrNotFound(err) {
+		return err
+	}
+
+	encodedAuth, err := opts.encodedRegistryAuth(ctx, dockerCli, image)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "Pulling image %s\n", image)
+	responseBody, err := client.ImagePull(ctx, image, types.ImagePullOptions{RegistryAuth: encodedAuth})
+	if err != nil {
+		return err
+	}
+	defer responseBody.Close()
+
+	return jsonmessage.DisplayJSONMessagesToStream(responseBody, dockerCli.Out(), nil)
+}
+
+// removeLocal removes the containers and networks of a local stack, and
+// optionally its volumes
+func removeLocal(ctx context.Context, dockerCli *command.DockerCli, opts RemoveOptions) error {
+	client := dockerCli.Client()
+	namespace := opts.namespace
+
+	containers, err := client.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: getStackFilter(namespace)})
+	if err != nil {
+		return err
+	}
+
+	networks, err := getStackNetworks(ctx, client, namespace)
+	if err != nil {
+		return err
+	}
+
+	var volumes []*types.Volume
+	if opts.volumes {
+		volumes, err = getStackVolumes(ctx, dockerCli, namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(containers)+len(networks)+len(volumes) == 0 {
+		fmt.Fprintf(dockerCli.Out(), "Nothing found in stack: %s\n", namespace)
+		return nil
+	}
+
+	hasError := removeLocalContainers(ctx, dockerCli, containers)
+	hasError = removeNetworks(ctx, dockerCli, networks) || hasError
+	if opts.volumes {
+		hasError = removeStackVolumes(ctx, dockerCli, namespace) || hasError
+	}
+
+	if hasError {
+		return fmt.Errorf("Failed to remove some resources")
+	}
+	return nil
+}
+
+func removeLocalContainers(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	containers []types.Container,
+) bool {
+	var err error
+	for _, localContainer := range containers {
+		name := strings.TrimPrefix(localContainer.Names[0], "/")
+		fmt.Fprintf(dockerCli.Err(), "Removing container %s\n", name)
+		if err = dockerCli.Client().ContainerRemove(ctx, localContainer.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			fmt.Fprintf(dockerCli.Err(), "Failed to remove container %s: %s\n", name, err)
+		}
+	}
+	return err != nil
+}
@@ -0,0 +1,294 @@
+package stack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+const (
+	// Log detail key that the engine adds to service logs
+	logDetailTaskID = "com.docker.swarm.task.id"
+)
+
+/**
+ * Stack logs
+ *
+ * Logs are read for each service in the stack, or a single service, and each
+ * line is prefixed with the service and task it came from.
+ */
+
+type LogsOptions struct {
+	namespace  string
+	service    string
+	follow     bool
+	since      string
+	tail       string
+	timestamps bool
+
+	out io.Writer
+	err io.Writer
+}
+
+func New_LogsOptions(namespace string) *LogsOptions {
+	return &LogsOptions{
+		namespace: namespace,
+		tail:      "all",
+	}
+}
+
+// Only show the logs of one service, by its name in the stack
+func (opts *LogsOptions) SetService(service string) {
+	opts.service = service
+}
+
+// Keep streaming new log lines until the context is cancelled
+func (opts *LogsOptions) SetFollow(follow bool) {
+	opts.follow = follow
+}
+
+// Only show lines since a timestamp or relative duration, such as 10m
+func (opts *LogsOptions) SetSince(since string) {
+	opts.since = since
+}
+
+// Number of lines to show from the end of the logs, or "all"
+func (opts *LogsOptions) SetTail(tail string) {
+	opts.tail = tail
+}
+
+// Show the timestamp of each line
+func (opts *LogsOptions) SetTimestamps(timestamps bool) {
+	opts.timestamps = timestamps
+}
+
+// Write the logs somewhere other than the cli output, nil writers use the cli
+func (opts *LogsOptions) SetOutput(out, err io.Writer) {
+	opts.out = out
+	opts.err = err
+}
+
+// RunLogs streams the logs of the stack services until they end, or the
+// context is cancelled when following.
+func RunLogs(ctx context.Context, dockerCli *command.DockerCli, opts LogsOptions) error {
+	client := dockerCli.Client()
+
+	out, errOut := opts.out, opts.err
+	if out == nil {
+		out = dockerCli.Out()
+	}
+	if errOut == nil {
+		errOut = dockerCli.Err()
+	}
+
+	services, err := getServices(ctx, client, opts.namespace)
+	if err != nil {
+		return err
+	}
+	if opts.service != "" {
+		services, err = filterServicesByName(services, opts.namespace, opts.service)
+		if err != nil {
+			return err
+		}
+	}
+	if len(services) == 0 {
+		fmt.Fprintf(errOut, "Nothing found in stack: %s\n", opts.namespace)
+		return nil
+	}
+	sortServices(services)
+
+	return streamServiceLogs(ctx, dockerCli, services, opts, out, errOut)
+}
+
+// streamServiceLogs streams the logs of the given services to out and errOut
+func streamServiceLogs(ctx context.Context, dockerCli *command.DockerCli, services []swarm.Service, opts LogsOptions, out, errOut io.Writer) error {
+	client := dockerCli.Client()
+
+	tasks, err := taskNames(ctx, dockerCli, services)
+	if err != nil {
+		return err
+	}
+
+	logOptions := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Since:      opts.since,
+		Timestamps: opts.timestamps,
+		Follow:     opts.follow,
+		Tail:       opts.tail,
+		Details:    true,
+	}
+
+	// lines from the services are written whole, so they do not interleave
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	errs := make([]error, len(services))
+
+	for index, service := range services {
+		wg.Add(1)
+		go func(index int, service swarm.Service) {
+			defer wg.Done()
+
+			responseBody, err := client.ServiceLogs(ctx, service.ID, logOptions)
+			if err != nil {
+				errs[index] = fmt.Errorf("Failed to read logs for service %s: %s", service.Spec.Name, err)
+				return
+			}
+			defer responseBody.Close()
+
+			stdout := &logWriter{out: out, lock: &lock, service: service, tasks: tasks, timestamps: opts.timestamps}
+			stderr := &logWriter{out: errOut, lock: &lock, service: service, tasks: tasks, timestamps: opts.timestamps}
+
+			if service.Spec.TaskTemplate.ContainerSpec.TTY {
+				_, err = io.Copy(stdout, responseBody)
+			} else {
+				_, err = stdcopy.StdCopy(stdout, stderr, responseBody)
+			}
+			stdout.Flush()
+			stderr.Flush()
+
+			if err != nil && ctx.Err() == nil {
+				errs[index] = fmt.Errorf("Failed reading logs for service %s: %s", service.Spec.Name, err)
+			}
+		}(index, service)
+	}
+	wg.Wait()
+
+	var messages []string
+	for _, err := range errs {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	if len(messages) > 0 {
+		return fmt.Errorf("%s", strings.Join(messages, "\n"))
+	}
+	return nil
+}
+
+// filterServicesByName finds a stack service, by its name with or without
+// the stack namespace
+func filterServicesByName(services []swarm.Service, namespace, name string) ([]swarm.Service, error) {
+	for _, service := range services {
+		if service.Spec.Name == name || service.Spec.Name == namespace+"_"+name {
+			return []swarm.Service{service}, nil
+		}
+	}
+	return nil, fmt.Errorf("Service %s not found in stack: %s", name, namespace)
+}
+
+// taskNames maps task IDs to names like the docker cli uses, such as
+// ns_web.1 for replicated tasks, or ns_web.<node id> for global tasks
+func taskNames(ctx context.Context, dockerCli *command.DockerCli, services []swarm.Service) (map[string]string, error) {
+	taskFilter := filters.NewArgs()
+	serviceNames := map[string]string{}
+	for _, service := range services {
+		taskFilter.Add("service", service.ID)
+		serviceNames[service.ID] = service.Spec.Name
+	}
+
+	tasks, err := dockerCli.Client().TaskList(ctx, types.TaskListOptions{Filters: taskFilter})
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]string{}
+	for _, task := range tasks {
+		if task.Slot > 0 {
+			names[task.ID] = fmt.Sprintf("%s.%d", serviceNames[task.ServiceID], task.Slot)
+		} else {
+			names[task.ID] = fmt.Sprintf("%s.%s", serviceNames[task.ServiceID], task.NodeID)
+		}
+	}
+	return names, nil
+}
+
+// logWriter splits service logs into lines, and writes each line with a
+// prefix naming the task, in place of the log details
+type logWriter struct {
+	out        io.Writer
+	lock       *sync.Mutex
+	service    swarm.Service
+	tasks      map[string]string
+	timestamps bool
+
+	buffer bytes.Buffer
+}
+
+func (writer *logWriter) Write(data []byte) (int, error) {
+	writer.buffer.Write(data)
+
+	for {
+		line, err := writer.buffer.ReadBytes('\n')
+		if err != nil {
+			// keep a partial line until the rest arrives
+			writer.buffer.Write(line)
+			break
+		}
+		writer.writeLine(string(line))
+	}
+	return len(data), nil
+}
+
+// Flush writes any partial line left in the buffer
+func (writer *logWriter) Flush() {
+	if writer.buffer.Len() > 0 {
+		writer.writeLine(writer.buffer.String() + "\n")
+		writer.buffer.Reset()
+	}
+}
+
+func (writer *logWriter) writeLine(line string) {
+	var timestamp string
+	if writer.timestamps {
+		if parts := strings.SplitN(line, " ", 2); len(parts) == 2 {
+			timestamp, line = parts[0]+" ", parts[1]
+		}
+	}
+
+	details := map[string]string{}
+	if parts := strings.SplitN(line, " ", 2); len(parts) == 2 && strings.Contains(parts[0], "=") {
+		details = parseLogDetails(parts[0])
+		line = parts[1]
+	}
+
+	name, exists := writer.tasks[details[logDetailTaskID]]
+	if !exists {
+		name = writer.service.Spec.Name
+		if taskID := details[logDetailTaskID]; taskID != "" {
+			name = fmt.Sprintf("%s.%s", name, stringid(taskID))
+		}
+	}
+
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	fmt.Fprintf(writer.out, "%s%s | %s", timestamp, name, line)
+}
+
+// parseLogDetails parses the comma separated key=value log details
+func parseLogDetails(raw string) map[string]string {
+	details := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		if parts := strings.SplitN(pair, "=", 2); len(parts) == 2 {
+			details[parts[0]] = parts[1]
+		}
+	}
+	return details
+}
+
+// stringid shortens an ID the way the docker cli does
+func stringid(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -3,8 +3,12 @@ package stack
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/cli/command"
 	"github.com/docker/docker/cli/command/formatter"
 	"github.com/docker/docker/cli/command/idresolver"
@@ -21,6 +25,44 @@ type PsOptions struct {
 	format    string
 }
 
+func New_PsOptions(namespace string) *PsOptions {
+	return &PsOptions{
+		filter:    opts.NewFilterOpt(),
+		namespace: namespace,
+	}
+}
+
+// Write the tasks as a table, json, yaml or a Go template
+func (opts *PsOptions) SetFormat(format string) {
+	opts.format = format
+}
+
+// stackTasks is the json and yaml schema of the stack tasks
+type stackTasks struct {
+	Namespace string      `json:"namespace" yaml:"namespace"`
+	Tasks     []stackTask `json:"tasks" yaml:"tasks"`
+}
+
+// stackTask describes a single task, with the names of its service and node
+type stackTask struct {
+	ID   string `json:"id" yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+	// ServiceID and ServiceName identify the service the task belongs to
+	ServiceID   string `json:"service_id" yaml:"service_id"`
+	ServiceName string `json:"service_name" yaml:"service_name"`
+	Image       string `json:"image" yaml:"image"`
+	// NodeID and NodeName are empty until the task is assigned to a node
+	NodeID       string    `json:"node_id,omitempty" yaml:"node_id,omitempty"`
+	NodeName     string    `json:"node_name,omitempty" yaml:"node_name,omitempty"`
+	ContainerID  string    `json:"container_id,omitempty" yaml:"container_id,omitempty"`
+	DesiredState string    `json:"desired_state" yaml:"desired_state"`
+	CurrentState string    `json:"current_state" yaml:"current_state"`
+	StateSince   time.Time `json:"state_since" yaml:"state_since"`
+	Error        string    `json:"error,omitempty" yaml:"error,omitempty"`
+	// Ports are the ports published on the node by the task, as published:target/protocol
+	Ports []string `json:"ports,omitempty" yaml:"ports,omitempty"`
+}
+
 func RunPS(dockerCli *command.DockerCli, opts PsOptions) error {
 	namespace := opts.namespace
 	client := dockerCli.Client()
@@ -33,6 +75,15 @@ func RunPS(dockerCli *command.DockerCli, opts PsOptions) error {
 		return err
 	}
 
+	// structured output is an empty document for an empty stack
+	if isStructuredFormat(opts.format) {
+		structured, err := getStackTasks(ctx, dockerCli, namespace, tasks)
+		if err != nil {
+			return err
+		}
+		return writeStructured(dockerCli.Out(), opts.format, structured)
+	}
+
 	if len(tasks) == 0 {
 		fmt.Fprintf(dockerCli.Out(), "Nothing found in stack: %s\n", namespace)
 		return nil
@@ -49,3 +100,72 @@ func RunPS(dockerCli *command.DockerCli, opts PsOptions) error {
 
 	return task.Print(dockerCli, ctx, tasks, idresolver.New(client, opts.noResolve), !opts.noTrunc, opts.quiet, format)
 }
+
+// getStackTasks resolves the service and node names of tasks
+func getStackTasks(ctx context.Context, dockerCli *command.DockerCli, namespace string, tasks []swarm.Task) (stackTasks, error) {
+	client := dockerCli.Client()
+
+	structured := stackTasks{Namespace: namespace, Tasks: []stackTask{}}
+	if len(tasks) == 0 {
+		return structured, nil
+	}
+
+	serviceFilter := filters.NewArgs()
+	for _, task := range tasks {
+		serviceFilter.Add("id", task.ServiceID)
+	}
+	services, err := client.ServiceList(ctx, types.ServiceListOptions{Filters: serviceFilter})
+	if err != nil {
+		return stackTasks{}, err
+	}
+	serviceNames := map[string]string{}
+	for _, service := range services {
+		serviceNames[service.ID] = service.Spec.Name
+	}
+
+	nodes, err := client.NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return stackTasks{}, err
+	}
+	nodeNames := map[string]string{}
+	for _, node := range nodes {
+		nodeNames[node.ID] = node.Description.Hostname
+	}
+
+	for _, task := range tasks {
+		serviceName := serviceNames[task.ServiceID]
+		name := fmt.Sprintf("%s.%d", serviceName, task.Slot)
+		if task.Slot == 0 {
+			// global service tasks are named by their node
+			name = fmt.Sprintf("%s.%s", serviceName, task.NodeID)
+		}
+
+		item := stackTask{
+			ID:           task.ID,
+			Name:         name,
+			ServiceID:    task.ServiceID,
+			ServiceName:  serviceName,
+			Image:        task.Spec.ContainerSpec.Image,
+			NodeID:       task.NodeID,
+			NodeName:     nodeNames[task.NodeID],
+			ContainerID:  task.Status.ContainerStatus.ContainerID,
+			DesiredState: string(task.DesiredState),
+			CurrentState: string(task.Status.State),
+			StateSince:   task.Status.Timestamp,
+			Error:        task.Status.Err,
+		}
+		for _, port := range task.Status.PortStatus.Ports {
+			item.Ports = append(item.Ports, fmt.Sprintf("%d:%d/%s", port.PublishedPort, port.TargetPort, port.Protocol))
+		}
+		structured.Tasks = append(structured.Tasks, item)
+	}
+
+	sort.Sort(stackTasksByName(structured.Tasks))
+	return structured, nil
+}
+
+type stackTasksByName []stackTask
+
+func (n stackTasksByName) Len() int           { return len(n) }
+func (n stackTasksByName) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
+func (n stackTasksByName) Less(i, j int) bool { return n[i].Name < n[j].Name }
@@ -0,0 +1,182 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/cli/command"
+)
+
+const (
+	// Registry host used for images without a registry in their name
+	defaultRegistryHost = "docker.io"
+)
+
+/**
+ * Registry auth resolution
+ *
+ * A resolver provides the encoded registry auth that is sent to the swarm
+ * with a service, so that the nodes can pull the service image.
+ */
+
+// RegistryAuthResolver provides encoded registry auth for an image
+type RegistryAuthResolver interface {
+	// EncodedRegistryAuth for the image, or an empty string for no auth
+	EncodedRegistryAuth(ctx context.Context, dockerCli *command.DockerCli, image string) (string, error)
+}
+
+// CliRegistryAuthResolver uses the docker cli config of the user running radi
+type CliRegistryAuthResolver struct{}
+
+// Constructor for CliRegistryAuthResolver
+func New_CliRegistryAuthResolver() *CliRegistryAuthResolver {
+	return &CliRegistryAuthResolver{}
+}
+
+// EncodedRegistryAuth from the docker cli config
+func (resolver *CliRegistryAuthResolver) EncodedRegistryAuth(ctx context.Context, dockerCli *command.DockerCli, image string) (string, error) {
+	return command.RetrieveAuthTokenFromImage(ctx, dockerCli, image)
+}
+
+// RegistryCredential holds the credentials for a single registry
+type RegistryCredential struct {
+	Username      string `yaml:"Username"`
+	Password      string `yaml:"Password"`
+	Email         string `yaml:"Email"`
+	IdentityToken string `yaml:"IdentityToken"`
+}
+
+// RegistryCredentialsResolver uses credentials keyed by registry host, falling
+// back to another resolver (which may be nil) for registries it has no
+// credentials for.
+type RegistryCredentialsResolver struct {
+	credentials map[string]RegistryCredential
+	fallback    RegistryAuthResolver
+}
+
+// Constructor for RegistryCredentialsResolver
+func New_RegistryCredentialsResolver(credentials map[string]RegistryCredential, fallback RegistryAuthResolver) *RegistryCredentialsResolver {
+	normalized := map[string]RegistryCredential{}
+	for host, credential := range credentials {
+		normalized[normalizeRegistryHost(host)] = credential
+	}
+
+	return &RegistryCredentialsResolver{
+		credentials: normalized,
+		fallback:    fallback,
+	}
+}
+
+// EncodedRegistryAuth for the registry host of the image
+func (resolver *RegistryCredentialsResolver) EncodedRegistryAuth(ctx context.Context, dockerCli *command.DockerCli, image string) (string, error) {
+	host := registryHost(image)
+
+	credential, exists := resolver.credentials[host]
+	if !exists {
+		if resolver.fallback == nil {
+			return "", nil
+		}
+		return resolver.fallback.EncodedRegistryAuth(ctx, dockerCli, image)
+	}
+
+	return command.EncodeAuthToBase64(types.AuthConfig{
+		Username:      credential.Username,
+		Password:      credential.Password,
+		Email:         credential.Email,
+		IdentityToken: credential.IdentityToken,
+		ServerAddress: host,
+	})
+}
+
+// RegistryCredentialsFileResolver reads registry credentials, keyed by
+// registry host, from a yml file the first time they are needed:
+//
+//	registry.example.com:
+//	  Username: ci
+//	  Password: secret
+type RegistryCredentialsFileResolver struct {
+	path     string
+	fallback RegistryAuthResolver
+
+	load     sync.Once
+	resolver *RegistryCredentialsResolver
+	err      error
+}
+
+// Constructor for RegistryCredentialsFileResolver
+func New_RegistryCredentialsFileResolver(path string, fallback RegistryAuthResolver) *RegistryCredentialsFileResolver {
+	return &RegistryCredentialsFileResolver{
+		path:     path,
+		fallback: fallback,
+	}
+}
+
+// EncodedRegistryAuth for the registry host of the image
+func (resolver *RegistryCredentialsFileResolver) EncodedRegistryAuth(ctx context.Context, dockerCli *command.DockerCli, image string) (string, error) {
+	resolver.load.Do(func() {
+		bytes, err := ioutil.ReadFile(resolver.path)
+		if err != nil {
+			resolver.err = err
+			return
+		}
+
+		credentials := map[string]RegistryCredential{}
+		if err := yaml.Unmarshal(bytes, &credentials); err != nil {
+			resolver.err = fmt.Errorf("Error reading registry credentials %s: %v", resolver.path, err)
+			return
+		}
+		resolver.resolver = New_RegistryCredentialsResolver(credentials, resolver.fallback)
+	})
+
+	if resolver.err != nil {
+		return "", resolver.err
+	}
+	return resolver.resolver.EncodedRegistryAuth(ctx, dockerCli, image)
+}
+
+// registryHost returns the registry host for an image.  The first part of the
+// image name is only a host if it looks like one, otherwise the image is
+// from the default registry.
+func registryHost(image string) string {
+	slash := strings.Index(image, "/")
+	if slash == -1 {
+		return defaultRegistryHost
+	}
+
+	host := image[:slash]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return defaultRegistryHost
+	}
+	return normalizeRegistryHost(host)
+}
+
+// normalizeRegistryHost maps the various names for the default registry to
+// one, so that credentials can be keyed by any of them
+func normalizeRegistryHost(host string) string {
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host = strings.TrimSuffix(host, "/v1/")
+	switch host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return defaultRegistryHost
+	}
+	return host
+}
+
+// encodedRegistryAuth uses the configured resolver, or the docker cli config
+// if sendRegistryAuth is set, or no auth at all
+func (opts DeployOptions) encodedRegistryAuth(ctx context.Context, dockerCli *command.DockerCli, image string) (string, error) {
+	switch {
+	case opts.registryAuth != nil:
+		return opts.registryAuth.EncodedRegistryAuth(ctx, dockerCli, image)
+	case opts.sendRegistryAuth:
+		return New_CliRegistryAuthResolver().EncodedRegistryAuth(ctx, dockerCli, image)
+	default:
+		return "", nil
+	}
+}
@@ -11,19 +11,37 @@ import (
 
 type RemoveOptions struct {
 	namespace string
+	volumes   bool
+	mode      string
 }
 
 func New_RemoveOptions(namespace string) *RemoveOptions {
 	return &RemoveOptions{
 		namespace: namespace,
+		mode:      DeployModeSwarm,
 	}
 }
 
+// Also remove the stack volumes on the connected node, once the stack
+// containers there are gone.  Other swarm nodes keep their copies.
+func (opts *RemoveOptions) SetVolumes(volumes bool) {
+	opts.volumes = volumes
+}
+
+// Remove a swarm stack, or the containers of a local stack
+func (opts *RemoveOptions) SetMode(mode string) {
+	opts.mode = mode
+}
+
 func RunRemove(dockerCli *command.DockerCli, opts RemoveOptions) error {
 	namespace := opts.namespace
 	client := dockerCli.Client()
 	ctx := context.Background()
 
+	if opts.mode == DeployModeLocal {
+		return removeLocal(ctx, dockerCli, opts)
+	}
+
 	services, err := getServices(ctx, client, namespace)
 	if err != nil {
 		return err
@@ -39,14 +57,31 @@ func RunRemove(dockerCli *command.DockerCli, opts RemoveOptions) error {
 		return err
 	}
 
-	if len(services)+len(networks)+len(secrets) == 0 {
+	configs, err := getStackConfigs(ctx, client, namespace)
+	if err != nil {
+		return err
+	}
+
+	var volumes []*types.Volume
+	if opts.volumes {
+		volumes, err = getStackVolumes(ctx, dockerCli, namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(services)+len(networks)+len(secrets)+len(configs)+len(volumes) == 0 {
 		fmt.Fprintf(dockerCli.Out(), "Nothing found in stack: %s\n", namespace)
 		return nil
 	}
 
 	hasError := removeServices(ctx, dockerCli, services)
 	hasError = removeSecrets(ctx, dockerCli, secrets) || hasError
+	hasError = removeConfigs(ctx, dockerCli, configs) || hasError
 	hasError = removeNetworks(ctx, dockerCli, networks) || hasError
+	if opts.volumes {
+		hasError = removeStackVolumes(ctx, dockerCli, namespace) || hasError
+	}
 
 	if hasError {
 		return fmt.Errorf("Failed to remove some resources")
@@ -98,3 +133,18 @@ func removeSecrets(
 	}
 	return err != nil
 }
+
+func removeConfigs(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	configs []swarm.Config,
+) bool {
+	var err error
+	for _, config := range configs {
+		fmt.Fprintf(dockerCli.Err(), "Removing config %s\n", config.Spec.Name)
+		if err = dockerCli.Client().ConfigRemove(ctx, config.ID); err != nil {
+			fmt.Fprintf(dockerCli.Err(), "Failed to remove config %s: %s", config.ID, err)
+		}
+	}
+	return err != nil
+}
@@ -0,0 +1,86 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/client"
+)
+
+// pinImageDigests resolves the image of every service to its digest through
+// the registry, and pins the service specs to that digest, so that every
+// node runs exactly the same image.  A map of each image as written to the
+// image it was pinned to is returned.
+func pinImageDigests(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	services map[string]swarm.ServiceSpec,
+	opts DeployOptions,
+) (map[string]string, error) {
+	pinned := map[string]string{}
+
+	internalNames := make([]string, 0, len(services))
+	for internalName := range services {
+		internalNames = append(internalNames, internalName)
+	}
+	sort.Strings(internalNames)
+
+	for _, internalName := range internalNames {
+		serviceSpec := services[internalName]
+		image := serviceSpec.TaskTemplate.ContainerSpec.Image
+
+		if _, resolved := pinned[image]; !resolved {
+			digested, err := resolveImageDigest(ctx, dockerCli, dockerCli.Client(), image, opts)
+			if err != nil {
+				return pinned, fmt.Errorf("Could not resolve image %s for service %s: %s", image, internalName, err)
+			}
+			pinned[image] = digested
+
+			if digested != image {
+				fmt.Fprintf(dockerCli.Out(), "Pinning image %s to %s\n", image, digested)
+			}
+		}
+
+		serviceSpec.TaskTemplate.ContainerSpec.Image = pinned[image]
+		services[internalName] = serviceSpec
+	}
+
+	return pinned, nil
+}
+
+// resolveImageDigest looks up the digest for an image reference using the
+// distribution inspect API, returning a name@digest reference.  The daemon
+// asks the registry, so its registry mirrors, proxy and insecure registries
+// apply.
+func resolveImageDigest(ctx context.Context, dockerCli *command.DockerCli, distribution client.DistributionAPIClient, image string, opts DeployOptions) (string, error) {
+	if strings.Contains(image, "@") {
+		// already pinned to a digest
+		return image, nil
+	}
+
+	encodedAuth, err := opts.encodedRegistryAuth(ctx, dockerCli, image)
+	if err != nil {
+		return "", err
+	}
+
+	distributionInspect, err := distribution.DistributionInspect(ctx, image, encodedAuth)
+	if err != nil {
+		return "", err
+	}
+
+	return imageName(image) + "@" + distributionInspect.Descriptor.Digest.String(), nil
+}
+
+// imageName strips any tag from an image reference.  A colon is only a tag
+// separator when it comes after the last slash, as a registry host may
+// include a port.
+func imageName(image string) string {
+	if colon := strings.LastIndex(image, ":"); colon > strings.LastIndex(image, "/") {
+		return image[:colon]
+	}
+	return image
+}
@@ -0,0 +1,133 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/client"
+)
+
+const testDigest = "sha256:4bcb3d1ba4b9c6f34e1c3a1b4ba8a4a4de1ddda2f0b1e3b0c9b5c1b3a7e5c2d1"
+
+// newTestRegistry stands in for a daemon that asks its registry about
+// registry.example.com/app:1.0, which needs the registry auth if auth is not
+// empty
+func newTestRegistry(t *testing.T, auth string) (*httptest.Server, client.DistributionAPIClient) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		image := strings.TrimSuffix(r.URL.Path[strings.Index(r.URL.Path, "/distribution/")+len("/distribution/"):], "/json")
+		if image != "registry.example.com/app:1.0" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"message": "manifest unknown for %s"}`, image)
+			return
+		}
+		if auth != "" && r.Header.Get("X-Registry-Auth") != auth {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message": "unauthorized"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"Descriptor": {"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "digest": %q, "size": 1234}}`, testDigest)
+	}))
+
+	apiclient, err := client.NewClient("tcp://"+strings.TrimPrefix(server.URL, "http://"), configsAPIVersion, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, apiclient
+}
+
+// testAuthResolver gives the same registry auth for every image
+type testAuthResolver string
+
+func (resolver testAuthResolver) EncodedRegistryAuth(ctx context.Context, dockerCli *command.DockerCli, image string) (string, error) {
+	return string(resolver), nil
+}
+
+func TestResolveImageDigestPinsTag(t *testing.T) {
+	server, apiclient := newTestRegistry(t, "")
+	defer server.Close()
+
+	digested, err := resolveImageDigest(context.Background(), nil, apiclient, "registry.example.com/app:1.0", *New_DeployOptions("", "", "test", false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "registry.example.com/app@" + testDigest; digested != expected {
+		t.Errorf("expected %s, got %s", expected, digested)
+	}
+}
+
+func TestResolveImageDigestSendsRegistryAuth(t *testing.T) {
+	server, apiclient := newTestRegistry(t, "encoded-auth")
+	defer server.Close()
+
+	opts := New_DeployOptions("", "", "test", false)
+	opts.SetRegistryAuthResolver(testAuthResolver("encoded-auth"))
+	digested, err := resolveImageDigest(context.Background(), nil, apiclient, "registry.example.com/app:1.0", *opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "registry.example.com/app@" + testDigest; digested != expected {
+		t.Errorf("expected %s, got %s", expected, digested)
+	}
+
+	if digested, err := resolveImageDigest(context.Background(), nil, apiclient, "registry.example.com/app:1.0", *New_DeployOptions("", "", "test", false)); err == nil {
+		t.Errorf("expected an error without registry auth, got %s", digested)
+	}
+}
+
+func TestResolveImageDigestKeepsPinnedImage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("daemon asked for %s for an image that is already pinned", r.URL.Path)
+	}))
+	defer server.Close()
+
+	apiclient, err := client.NewClient("tcp://"+strings.TrimPrefix(server.URL, "http://"), configsAPIVersion, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	image := "registry.example.com/app@" + testDigest
+	digested, err := resolveImageDigest(context.Background(), nil, apiclient, image, *New_DeployOptions("", "", "test", false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digested != image {
+		t.Errorf("expected %s to stay unchanged, got %s", image, digested)
+	}
+}
+
+func TestResolveImageDigestUnreachableDaemon(t *testing.T) {
+	server, apiclient := newTestRegistry(t, "")
+	server.Close()
+
+	if digested, err := resolveImageDigest(context.Background(), nil, apiclient, "registry.example.com/app:1.0", *New_DeployOptions("", "", "test", false)); err == nil {
+		t.Errorf("expected an error for an unreachable daemon, got %s", digested)
+	}
+}
+
+func TestResolveImageDigestUnknownTag(t *testing.T) {
+	server, apiclient := newTestRegistry(t, "")
+	defer server.Close()
+
+	if digested, err := resolveImageDigest(context.Background(), nil, apiclient, "registry.example.com/app:2.0", *New_DeployOptions("", "", "test", false)); err == nil {
+		t.Errorf("expected an error for an unknown tag, got %s", digested)
+	}
+}
+
+func TestImageName(t *testing.T) {
+	for image, expected := range map[string]string{
+		"nginx":                           "nginx",
+		"nginx:1.13":                      "nginx",
+		"localhost:5000/app":              "localhost:5000/app",
+		"registry.example.com:5000/a/b:2": "registry.example.com:5000/a/b",
+	} {
+		if name := imageName(image); name != expected {
+			t.Errorf("%s: expected %s, got %s", image, expected, name)
+		}
+	}
+}
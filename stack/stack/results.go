@@ -0,0 +1,69 @@
+package stack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+// ServiceResult is the outcome of an operation on a single stack service
+type ServiceResult struct {
+	// Name is the namespaced name of the service
+	Name string
+	// ID is the swarm ID of the service
+	ID string
+	// Action describes what was done to the service, such as create or update
+	Action string
+	// Retries is how often the action was retried because of version conflicts
+	Retries int
+	// Rollout is the final update state, if the rollout was monitored
+	Rollout string
+	// Error is set if the operation failed for this service
+	Error error
+}
+
+// ServiceResults holds the outcomes of an operation across stack services
+type ServiceResults []ServiceResult
+
+func (results ServiceResults) Len() int           { return len(results) }
+func (results ServiceResults) Swap(i, j int)      { results[i], results[j] = results[j], results[i] }
+func (results ServiceResults) Less(i, j int) bool { return results[i].Name < results[j].Name }
+
+// Failed returns only those results which carry an error
+func (results ServiceResults) Failed() ServiceResults {
+	failed := ServiceResults{}
+	for _, result := range results {
+		if result.Error != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Err aggregates all of the service errors into a single error, or nil if
+// every service succeeded
+func (results ServiceResults) Err() error {
+	failed := results.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	var msgs []string
+	for _, result := range failed {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", result.Name, result.Error))
+	}
+	return fmt.Errorf("Failed for %d of %d services:\n%s", len(failed), len(results), strings.Join(msgs, "\n"))
+}
+
+type servicesByName []swarm.Service
+
+func (n servicesByName) Len() int           { return len(n) }
+func (n servicesByName) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
+func (n servicesByName) Less(i, j int) bool { return n[i].Spec.Name < n[j].Spec.Name }
+
+// sortServices sorts services by name so that output is deterministic
+func sortServices(services []swarm.Service) {
+	sort.Sort(servicesByName(services))
+}
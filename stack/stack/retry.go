@@ -0,0 +1,66 @@
+package stack
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+)
+
+const (
+	// By default a conflicting service update is retried this many times
+	defaultUpdateRetries = 5
+	// Backoff between retries doubles from the first delay up to the max
+	updateRetryDelay    = 250 * time.Millisecond
+	updateRetryMaxDelay = 4 * time.Second
+)
+
+// isErrUpdateOutOfSequence tells if a service update failed because the
+// service was changed by someone else since its version was read.
+func isErrUpdateOutOfSequence(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "update out of sequence")
+}
+
+// updateServiceWithRetry updates a service, and if the update conflicts with
+// another change to the service, re-inspects the service and tries again
+// with a bounded backoff.  The spec function is given the current service
+// for each attempt.  The number of retries that were needed is returned.
+func updateServiceWithRetry(
+	ctx context.Context,
+	apiClient client.APIClient,
+	service swarm.Service,
+	retries int,
+	spec func(service swarm.Service) swarm.ServiceSpec,
+	updateOpts types.ServiceUpdateOptions,
+) (types.ServiceUpdateResponse, int, error) {
+	delay := updateRetryDelay
+
+	for attempt := 0; ; attempt++ {
+		response, err := apiClient.ServiceUpdate(
+			ctx,
+			service.ID,
+			service.Version,
+			spec(service),
+			updateOpts,
+		)
+		if err == nil || !isErrUpdateOutOfSequence(err) || attempt >= retries {
+			return response, attempt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return response, attempt, ctx.Err()
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > updateRetryMaxDelay {
+			delay = updateRetryMaxDelay
+		}
+
+		if service, _, err = apiClient.ServiceInspectWithRaw(ctx, service.ID); err != nil {
+			return response, attempt + 1, err
+		}
+	}
+}
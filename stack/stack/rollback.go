@@ -0,0 +1,81 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/cli/command"
+)
+
+type RollbackOptions struct {
+	namespace string
+}
+
+func New_RollbackOptions(namespace string) *RollbackOptions {
+	return &RollbackOptions{
+		namespace: namespace,
+	}
+}
+
+// RunRollback reverts every service in the stack to its PreviousSpec, which
+// is how `docker service update --rollback` is implemented.  A result is
+// returned for each service, so that a single failure does not hide the
+// state of the rest of the stack.
+func RunRollback(dockerCli *command.DockerCli, opts RollbackOptions) (ServiceResults, error) {
+	namespace := opts.namespace
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	services, err := getServices(ctx, client, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(services) == 0 {
+		fmt.Fprintf(dockerCli.Out(), "Nothing found in stack: %s\n", namespace)
+		return ServiceResults{}, nil
+	}
+
+	sortServices(services)
+
+	results := ServiceResults{}
+	for _, service := range services {
+		result := ServiceResult{
+			Name:   service.Spec.Name,
+			ID:     service.ID,
+			Action: "rollback",
+		}
+
+		if service.PreviousSpec == nil {
+			result.Error = fmt.Errorf("service %s does not have a previous spec to roll back to", service.Spec.Name)
+		} else {
+			fmt.Fprintf(dockerCli.Out(), "Rolling back service %s (id: %s)\n", service.Spec.Name, service.ID)
+
+			updateOpts := types.ServiceUpdateOptions{
+				RegistryAuthFrom: types.RegistryAuthFromPreviousSpec,
+			}
+			response, err := client.ServiceUpdate(
+				ctx,
+				service.ID,
+				service.Version,
+				*service.PreviousSpec,
+				updateOpts,
+			)
+			if err == nil {
+				for _, warning := range response.Warnings {
+					fmt.Fprintln(dockerCli.Err(), warning)
+				}
+			} else {
+				result.Error = err
+			}
+		}
+
+		if result.Error != nil {
+			fmt.Fprintf(dockerCli.Err(), "Failed to roll back service %s: %s\n", service.Spec.Name, result.Error)
+		}
+		results = append(results, result)
+	}
+
+	return results, results.Err()
+}
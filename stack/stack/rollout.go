@@ -0,0 +1,116 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/cli/command"
+)
+
+/**
+ * Rollout monitoring
+ *
+ * The compose update_config and restart_policy are converted into the
+ * service spec, so swarm rolls out updated services with them.  Swarm then
+ * tracks each rollout in the service UpdateStatus, which is followed here so
+ * that a paused or rolled back update fails the deploy.
+ */
+
+// monitorRollouts follows the rollouts of the updated services until they
+// complete, pause or roll back, or the timeout passes.  Services whose
+// rollout did not complete get an error in their result.  The rollout of
+// this deploy is told apart from earlier ones by its start time, which is
+// compared with the start time from before the update, as both come from
+// the manager clock.
+func monitorRollouts(
+	ctx context.Context,
+	dockerCli *command.DockerCli,
+	results ServiceResults,
+	previous []swarm.Service,
+	timeout time.Duration,
+) (ServiceResults, error) {
+	client := dockerCli.Client()
+
+	previousStarts := map[string]*time.Time{}
+	for _, service := range previous {
+		if service.UpdateStatus != nil {
+			previousStarts[service.ID] = service.UpdateStatus.StartedAt
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// only updates to the task template are rolled out
+	pending := map[int]swarm.UpdateState{}
+	for index, result := range results {
+		if result.Action != "update" || result.Error != nil {
+			continue
+		}
+		service, _, err := client.ServiceInspectWithRaw(ctx, result.ID)
+		if err != nil {
+			results[index].Error = err
+			continue
+		}
+		if service.PreviousSpec != nil && reflect.DeepEqual(service.PreviousSpec.TaskTemplate, service.Spec.TaskTemplate) {
+			continue
+		}
+		pending[index] = ""
+	}
+
+	for len(pending) > 0 {
+		for index, lastState := range pending {
+			result := &results[index]
+
+			service, _, err := client.ServiceInspectWithRaw(ctx, result.ID)
+			if err != nil {
+				result.Error = err
+				delete(pending, index)
+				continue
+			}
+
+			status := service.UpdateStatus
+			if status == nil || status.StartedAt == nil {
+				// the rollout of this deploy has not started yet
+				continue
+			}
+			if previousStart := previousStarts[service.ID]; previousStart != nil && status.StartedAt.Equal(*previousStart) {
+				// the status is still that of an earlier rollout
+				continue
+			}
+
+			if status.State != lastState {
+				fmt.Fprintf(dockerCli.Out(), "Service %s rollout %s %s\n", result.Name, status.State, status.Message)
+				pending[index] = status.State
+			}
+
+			switch status.State {
+			case swarm.UpdateStateUpdating, swarm.UpdateStateRollbackStarted:
+				continue
+			case swarm.UpdateStateCompleted:
+			default:
+				result.Error = fmt.Errorf("Update %s: %s", status.State, status.Message)
+			}
+			result.Rollout = string(status.State)
+			delete(pending, index)
+		}
+
+		if len(pending) == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			for index := range pending {
+				results[index].Error = fmt.Errorf("Rollout did not finish within %s", timeout)
+			}
+			return results, results.Err()
+		case <-time.After(convergePollInterval):
+		}
+	}
+
+	return results, results.Err()
+}
@@ -0,0 +1,216 @@
+package stack
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/cli/compose/convert"
+)
+
+const (
+	// Label on one-off job services, holding the service they were made from
+	labelRunService = "com.wunderkraut.radi.dockercli.run.service"
+	// How long to keep reading logs after a job ends, so that the last lines arrive
+	runLogsGrace = time.Second
+)
+
+/**
+ * One-off jobs
+ *
+ * A job is a copy of a stack service, with one replica that is not
+ * restarted, so it runs once with the same image, environment, secrets and
+ * networks as the service.  The job service is removed once it is done.
+ *
+ * Jobs do not carry the stack namespace label, so that stack operations such
+ * as ps, status and converge do not count them as stack services.
+ */
+
+type RunOptions struct {
+	namespace        string
+	service          string
+	command          []string
+	sendRegistryAuth bool
+
+	// registryAuth provides registry auth for the job image
+	registryAuth RegistryAuthResolver
+}
+
+func New_RunOptions(namespace string) *RunOptions {
+	return &RunOptions{
+		namespace: namespace,
+	}
+}
+
+// The stack service to copy the job from, by its name with or without the namespace
+func (opts *RunOptions) SetService(service string) {
+	opts.service = service
+}
+
+// Arguments to run instead of the service command
+func (opts *RunOptions) SetCommand(command []string) {
+	opts.command = command
+}
+
+// Send the registry auth from the docker cli config with the job
+func (opts *RunOptions) SetSendRegistryAuth(sendRegistryAuth bool) {
+	opts.sendRegistryAuth = sendRegistryAuth
+}
+
+// Use a resolver to provide the registry auth for the job image
+func (opts *RunOptions) SetRegistryAuthResolver(resolver RegistryAuthResolver) {
+	opts.registryAuth = resolver
+}
+
+// RunJob runs a one-off job derived from a stack service, streaming its logs,
+// and returns the exit code of the job.
+func RunJob(ctx context.Context, dockerCli *command.DockerCli, opts RunOptions) (int, error) {
+	client := dockerCli.Client()
+
+	if opts.service == "" {
+		return 0, fmt.Errorf("No service given to run a job from")
+	}
+
+	services, err := getServices(ctx, client, opts.namespace)
+	if err != nil {
+		return 0, err
+	}
+	services, err = filterServicesByName(services, opts.namespace, opts.service)
+	if err != nil {
+		return 0, err
+	}
+	service := services[0]
+
+	spec, err := jobSpec(service, opts.command)
+	if err != nil {
+		return 0, err
+	}
+
+	// auth is resolved the same way as for deploys
+	authOpts := DeployOptions{sendRegistryAuth: opts.sendRegistryAuth, registryAuth: opts.registryAuth}
+	encodedAuth, err := authOpts.encodedRegistryAuth(ctx, dockerCli, spec.TaskTemplate.ContainerSpec.Image)
+	if err != nil {
+		return 0, err
+	}
+
+	fmt.Fprintf(dockerCli.Err(), "Creating job %s\n", spec.Name)
+	response, err := client.ServiceCreate(ctx, spec, types.ServiceCreateOptions{EncodedRegistryAuth: encodedAuth})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		// remove the job even if the context was cancelled
+		fmt.Fprintf(dockerCli.Err(), "Removing job %s\n", spec.Name)
+		if err := client.ServiceRemove(context.Background(), response.ID); err != nil {
+			fmt.Fprintf(dockerCli.Err(), "Failed to remove job %s: %s\n", spec.Name, err)
+		}
+	}()
+
+	job, _, err := client.ServiceInspectWithRaw(ctx, response.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	// the job is not a stack service, so its logs are read directly
+	logsCtx, cancelLogs := context.WithCancel(ctx)
+	logsDone := make(chan error, 1)
+	go func() {
+		logsOpts := New_LogsOptions(opts.namespace)
+		logsOpts.SetFollow(true)
+		logsDone <- streamServiceLogs(logsCtx, dockerCli, []swarm.Service{job}, *logsOpts, dockerCli.Out(), dockerCli.Err())
+	}()
+
+	task, err := waitForJob(ctx, dockerCli, response.ID)
+
+	if err == nil {
+		time.Sleep(runLogsGrace)
+	}
+	cancelLogs()
+	if logsErr := <-logsDone; logsErr != nil {
+		fmt.Fprintf(dockerCli.Err(), "Failed to read job logs: %s\n", logsErr)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+	if task.Status.State != swarm.TaskStateComplete && task.Status.State != swarm.TaskStateFailed {
+		return 0, fmt.Errorf("Job %s did not run: %s %s", spec.Name, task.Status.State, task.Status.Err)
+	}
+	return task.Status.ContainerStatus.ExitCode, nil
+}
+
+// jobSpec derives a one-off job spec from a service spec.  The job name has
+// a random suffix, so that jobs started at the same time do not collide.
+func jobSpec(service swarm.Service, command []string) (swarm.ServiceSpec, error) {
+	spec := service.Spec
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return spec, err
+	}
+	spec.Name = service.Spec.Name + "_run_" + hex.EncodeToString(suffix)
+
+	spec.Labels = jobLabels(service.Spec.Labels)
+	spec.Labels[labelRunService] = service.Spec.Name
+
+	replicas := uint64(1)
+	spec.Mode = swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}}
+	spec.UpdateConfig = nil
+
+	// published ports belong to the long running service
+	if spec.EndpointSpec != nil {
+		spec.EndpointSpec = &swarm.EndpointSpec{Mode: spec.EndpointSpec.Mode}
+	}
+
+	containerSpec := spec.TaskTemplate.ContainerSpec
+	if len(command) > 0 {
+		containerSpec.Args = command
+	}
+	containerSpec.Labels = jobLabels(containerSpec.Labels)
+	spec.TaskTemplate.ContainerSpec = containerSpec
+	spec.TaskTemplate.RestartPolicy = &swarm.RestartPolicy{Condition: swarm.RestartPolicyConditionNone}
+
+	return spec, nil
+}
+
+// jobLabels copies service labels without the stack namespace label
+func jobLabels(serviceLabels map[string]string) map[string]string {
+	labels := map[string]string{}
+	for key, value := range serviceLabels {
+		if key != convert.LabelNamespace {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// waitForJob waits for the task of a job to reach a final state
+func waitForJob(ctx context.Context, dockerCli *command.DockerCli, serviceID string) (swarm.Task, error) {
+	taskFilter := filters.NewArgs()
+	taskFilter.Add("service", serviceID)
+
+	for {
+		tasks, err := dockerCli.Client().TaskList(ctx, types.TaskListOptions{Filters: taskFilter})
+		if err != nil {
+			return swarm.Task{}, err
+		}
+		for _, task := range tasks {
+			switch task.Status.State {
+			case swarm.TaskStateComplete, swarm.TaskStateFailed, swarm.TaskStateRejected, swarm.TaskStateShutdown:
+				return task, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return swarm.Task{}, ctx.Err()
+		case <-time.After(convergePollInterval):
+		}
+	}
+}
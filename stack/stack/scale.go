@@ -0,0 +1,146 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/cli/command"
+)
+
+type ScaleOptions struct {
+	namespace       string
+	scales          map[string]uint64
+	wait            bool
+	convergeTimeout time.Duration
+	updateRetries   int
+}
+
+func New_ScaleOptions(namespace string) *ScaleOptions {
+	return &ScaleOptions{
+		namespace:       namespace,
+		scales:          map[string]uint64{},
+		convergeTimeout: defaultConvergeTimeout,
+		updateRetries:   defaultUpdateRetries,
+	}
+}
+
+// Replica counts keyed by the service name, with or without the namespace
+func (opts *ScaleOptions) SetScales(scales map[string]uint64) {
+	opts.scales = scales
+}
+
+// Wait for the scaled services to converge
+func (opts *ScaleOptions) SetWait(wait bool) {
+	opts.wait = wait
+}
+
+// How long to wait for the services to converge
+func (opts *ScaleOptions) SetConvergeTimeout(timeout time.Duration) {
+	opts.convergeTimeout = timeout
+}
+
+// How often to retry a service update that conflicts with another change
+func (opts *ScaleOptions) SetUpdateRetries(retries int) {
+	opts.updateRetries = retries
+}
+
+// ParseScales parses scale arguments such as web=5, into replicas by service
+func ParseScales(args []string) (map[string]uint64, error) {
+	scales := map[string]uint64{}
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("Invalid scale %s, expected a service=replicas pair", arg)
+		}
+		replicas, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid replicas for service %s: %s", parts[0], parts[1])
+		}
+		scales[parts[0]] = replicas
+	}
+	return scales, nil
+}
+
+// RunScale sets the replica count of stack services, and optionally waits
+// for the stack to converge.  Global mode services are refused.
+func RunScale(dockerCli *command.DockerCli, opts ScaleOptions) (ServiceResults, error) {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	if len(opts.scales) == 0 {
+		return nil, fmt.Errorf("No services given to scale")
+	}
+
+	var names []string
+	for name := range opts.scales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// only services carrying the stack label can be scaled, so that names
+	// cannot reach the services of other stacks
+	stackServices, err := getServices(ctx, client, opts.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	results := ServiceResults{}
+	for _, name := range names {
+		replicas := opts.scales[name]
+		result := ServiceResult{Name: name, Action: "scale"}
+
+		services, err := filterServicesByName(stackServices, opts.namespace, name)
+		if err != nil {
+			result.Error = err
+			results = append(results, result)
+			continue
+		}
+		service := services[0]
+		result.Name = service.Spec.Name
+		result.ID = service.ID
+
+		if service.Spec.Mode.Replicated == nil {
+			result.Error = fmt.Errorf("Service %s is in global mode and cannot be scaled", result.Name)
+			results = append(results, result)
+			continue
+		}
+
+		fmt.Fprintf(dockerCli.Out(), "Scaling service %s to %d\n", result.Name, replicas)
+		_, result.Retries, result.Error = updateServiceWithRetry(
+			ctx,
+			client,
+			service,
+			opts.updateRetries,
+			func(service swarm.Service) swarm.ServiceSpec {
+				return scaledSpec(service, replicas)
+			},
+			types.ServiceUpdateOptions{},
+		)
+		results = append(results, result)
+	}
+
+	if err := results.Err(); err != nil {
+		return results, err
+	}
+
+	if opts.wait {
+		fmt.Fprintf(dockerCli.Out(), "Waiting for stack %s to converge\n", opts.namespace)
+		if err := waitForConvergence(ctx, client, opts.namespace, opts.convergeTimeout); err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// scaledSpec returns the spec of a replicated service with other replicas
+func scaledSpec(service swarm.Service, replicas uint64) swarm.ServiceSpec {
+	spec := service.Spec
+	spec.Mode = swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}}
+	return spec
+}
@@ -6,20 +6,52 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/cli/command"
 	"github.com/docker/docker/cli/command/formatter"
 	"github.com/docker/docker/cli/command/service"
 	"github.com/docker/docker/opts"
 )
 
-type servicesOptions struct {
+type ServicesOptions struct {
 	quiet     bool
 	format    string
 	filter    opts.FilterOpt
 	namespace string
 }
 
-func runServices(dockerCli *command.DockerCli, opts servicesOptions) error {
+func New_ServicesOptions(namespace string) *ServicesOptions {
+	return &ServicesOptions{
+		filter:    opts.NewFilterOpt(),
+		namespace: namespace,
+	}
+}
+
+// Write the services as a table, json, yaml or a Go template
+func (opts *ServicesOptions) SetFormat(format string) {
+	opts.format = format
+}
+
+// stackServices is the json and yaml schema of the stack services
+type stackServices struct {
+	Namespace string         `json:"namespace" yaml:"namespace"`
+	Services  []stackService `json:"services" yaml:"services"`
+}
+
+// stackService describes a single service, with its replica counts
+type stackService struct {
+	ID   string `json:"id" yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+	// Mode is replicated or global
+	Mode    string `json:"mode" yaml:"mode"`
+	Image   string `json:"image" yaml:"image"`
+	Desired uint64 `json:"desired" yaml:"desired"`
+	Running uint64 `json:"running" yaml:"running"`
+	// Ports are the published ports, as published:target/protocol
+	Ports []string `json:"ports,omitempty" yaml:"ports,omitempty"`
+}
+
+func RunServices(dockerCli *command.DockerCli, opts ServicesOptions) error {
 	ctx := context.Background()
 	client := dockerCli.Client()
 
@@ -31,6 +63,15 @@ func runServices(dockerCli *command.DockerCli, opts servicesOptions) error {
 
 	out := dockerCli.Out()
 
+	// structured output is an empty document for an empty stack
+	if isStructuredFormat(opts.format) {
+		structured, err := getStackServices(ctx, dockerCli, opts.namespace, services)
+		if err != nil {
+			return err
+		}
+		return writeStructured(out, opts.format, structured)
+	}
+
 	// if no services in this stack, print message and exit 0
 	if len(services) == 0 {
 		fmt.Fprintf(out, "Nothing found in stack: %s\n", opts.namespace)
@@ -72,3 +113,40 @@ func runServices(dockerCli *command.DockerCli, opts servicesOptions) error {
 	}
 	return formatter.ServiceListWrite(servicesCtx, services, info)
 }
+
+// getStackServices counts the desired and running replicas of services from
+// their tasks, in the same way as the stack status
+func getStackServices(ctx context.Context, dockerCli *command.DockerCli, namespace string, services []swarm.Service) (stackServices, error) {
+	structured := stackServices{Namespace: namespace, Services: []stackService{}}
+	if len(services) == 0 {
+		return structured, nil
+	}
+	sortServices(services)
+
+	taskFilter := filters.NewArgs()
+	for _, service := range services {
+		taskFilter.Add("service", service.ID)
+	}
+	tasks, err := dockerCli.Client().TaskList(ctx, types.TaskListOptions{Filters: taskFilter})
+	if err != nil {
+		return structured, err
+	}
+	tasksByService := map[string][]swarm.Task{}
+	for _, task := range tasks {
+		tasksByService[task.ServiceID] = append(tasksByService[task.ServiceID], task)
+	}
+
+	for _, service := range services {
+		status := serviceStatus(service, tasksByService[service.ID])
+		structured.Services = append(structured.Services, stackService{
+			ID:      service.ID,
+			Name:    service.Spec.Name,
+			Mode:    status.Mode,
+			Image:   service.Spec.TaskTemplate.ContainerSpec.Image,
+			Desired: status.Desired,
+			Running: status.Running,
+			Ports:   status.Ports,
+		})
+	}
+	return structured, nil
+}
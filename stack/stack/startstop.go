@@ -0,0 +1,173 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/cli/command"
+)
+
+const (
+	// Label on stopped services, holding the replicas to restore on start
+	labelStoppedReplicas = "com.wunderkraut.radi.dockercli.stopped.replicas"
+)
+
+/**
+ * Start and stop
+ *
+ * Stopping a stack scales its services to zero, and keeps the previous
+ * replica count in a service label, so that start can restore it.  The
+ * networks, secrets, configs and volumes of the stack stay in place.
+ */
+
+type StartStopOptions struct {
+	namespace       string
+	wait            bool
+	convergeTimeout time.Duration
+	updateRetries   int
+}
+
+func New_StartStopOptions(namespace string) *StartStopOptions {
+	return &StartStopOptions{
+		namespace:       namespace,
+		convergeTimeout: defaultConvergeTimeout,
+		updateRetries:   defaultUpdateRetries,
+	}
+}
+
+// Wait for the services to converge after starting or stopping
+func (opts *StartStopOptions) SetWait(wait bool) {
+	opts.wait = wait
+}
+
+// How long to wait for the services to converge
+func (opts *StartStopOptions) SetConvergeTimeout(timeout time.Duration) {
+	opts.convergeTimeout = timeout
+}
+
+// How often to retry a service update that conflicts with another change
+func (opts *StartStopOptions) SetUpdateRetries(retries int) {
+	opts.updateRetries = retries
+}
+
+// RunStop scales all replicated stack services to zero, remembering their
+// replica counts.  Global mode services cannot be scaled, and are skipped.
+func RunStop(dockerCli *command.DockerCli, opts StartStopOptions) (ServiceResults, error) {
+	return runStartStop(dockerCli, opts, "stop", func(service swarm.Service) (swarm.ServiceSpec, string) {
+		if service.Spec.Mode.Replicated == nil {
+			return service.Spec, "Service %s is in global mode and cannot be stopped\n"
+		}
+		if _, stopped := service.Spec.Labels[labelStoppedReplicas]; stopped {
+			return service.Spec, "Service %s is already stopped\n"
+		}
+
+		replicas := uint64(0)
+		if service.Spec.Mode.Replicated.Replicas != nil {
+			replicas = *service.Spec.Mode.Replicated.Replicas
+		}
+
+		spec := scaledSpec(service, 0)
+		spec.Labels = copyLabels(service.Spec.Labels)
+		spec.Labels[labelStoppedReplicas] = strconv.FormatUint(replicas, 10)
+		return spec, ""
+	})
+}
+
+// RunStart restores the replica counts of stopped stack services
+func RunStart(dockerCli *command.DockerCli, opts StartStopOptions) (ServiceResults, error) {
+	return runStartStop(dockerCli, opts, "start", func(service swarm.Service) (swarm.ServiceSpec, string) {
+		value, stopped := service.Spec.Labels[labelStoppedReplicas]
+		if !stopped || service.Spec.Mode.Replicated == nil {
+			return service.Spec, "Service %s is not stopped\n"
+		}
+
+		replicas, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			// a broken label should not keep the service down
+			replicas = 1
+		}
+
+		spec := scaledSpec(service, replicas)
+		spec.Labels = copyLabels(service.Spec.Labels)
+		delete(spec.Labels, labelStoppedReplicas)
+		return spec, ""
+	})
+}
+
+// runStartStop updates each stack service with the spec from the change
+// function, which can instead give a message about why a service is skipped
+func runStartStop(
+	dockerCli *command.DockerCli,
+	opts StartStopOptions,
+	action string,
+	change func(service swarm.Service) (swarm.ServiceSpec, string),
+) (ServiceResults, error) {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	services, err := getServices(ctx, client, opts.namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		fmt.Fprintf(dockerCli.Out(), "Nothing found in stack: %s\n", opts.namespace)
+		return ServiceResults{}, nil
+	}
+	sortServices(services)
+
+	results := ServiceResults{}
+	for _, service := range services {
+		result := ServiceResult{Name: service.Spec.Name, ID: service.ID, Action: action}
+
+		if _, skip := change(service); skip != "" {
+			fmt.Fprintf(dockerCli.Out(), skip, service.Spec.Name)
+			result.Action = "skip"
+			results = append(results, result)
+			continue
+		}
+
+		fmt.Fprintf(dockerCli.Out(), "%s service %s\n", startStopVerbs[action], service.Spec.Name)
+		_, result.Retries, result.Error = updateServiceWithRetry(
+			ctx,
+			client,
+			service,
+			opts.updateRetries,
+			func(service swarm.Service) swarm.ServiceSpec {
+				// the service may have changed between retries
+				spec, _ := change(service)
+				return spec
+			},
+			types.ServiceUpdateOptions{},
+		)
+		results = append(results, result)
+	}
+
+	if err := results.Err(); err != nil {
+		return results, err
+	}
+
+	if opts.wait {
+		fmt.Fprintf(dockerCli.Out(), "Waiting for stack %s to converge\n", opts.namespace)
+		if err := waitForConvergence(ctx, client, opts.namespace, opts.convergeTimeout); err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+var startStopVerbs = map[string]string{
+	"start": "Starting",
+	"stop":  "Stopping",
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	copied := map[string]string{}
+	for key, value := range labels {
+		copied[key] = value
+	}
+	return copied
+}
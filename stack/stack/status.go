@@ -0,0 +1,273 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/cli/command"
+)
+
+const (
+	// Verdicts for a stack and its services
+	StatusHealthy  = "healthy"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+
+	statusItemFmt = "%s\t%s\t%s\t%s\t%s\t%s\n"
+)
+
+/**
+ * Stack status
+ *
+ * The status summarizes each service from its spec, update status and tasks,
+ * and gives the stack a verdict: healthy if every service runs all of its
+ * replicas, down if services should run but none do, and degraded otherwise.
+ * Services scaled to zero or stopped are healthy, as nothing should run.  A
+ * global service should run on every node that swarm gave it a task for,
+ * which leaves out the nodes that its placement constraints exclude.
+ */
+
+type StatusOptions struct {
+	namespace string
+	format    string
+	out       io.Writer
+}
+
+func New_StatusOptions(namespace string) *StatusOptions {
+	return &StatusOptions{
+		namespace: namespace,
+		format:    FormatTable,
+	}
+}
+
+// Write the status as a table, json or yaml
+func (opts *StatusOptions) SetFormat(format string) {
+	opts.format = format
+}
+
+// Write the status to this writer, instead of the cli output
+func (opts *StatusOptions) SetOutput(out io.Writer) {
+	opts.out = out
+}
+
+// StackStatus summarizes the state of a stack
+type StackStatus struct {
+	Namespace string          `json:"namespace" yaml:"namespace"`
+	Status    string          `json:"status" yaml:"status"`
+	Services  []ServiceStatus `json:"services" yaml:"services"`
+}
+
+// ServiceStatus summarizes the state of a single stack service
+type ServiceStatus struct {
+	Name string `json:"name" yaml:"name"`
+	ID   string `json:"id" yaml:"id"`
+	// Mode is replicated or global
+	Mode    string `json:"mode" yaml:"mode"`
+	Desired uint64 `json:"desired" yaml:"desired"`
+	Running uint64 `json:"running" yaml:"running"`
+	// UpdateState is the state of the last update, if the service was updated
+	UpdateState   string `json:"update_state,omitempty" yaml:"update_state,omitempty"`
+	UpdateMessage string `json:"update_message,omitempty" yaml:"update_message,omitempty"`
+	// Ports are the published ports, as published:target/protocol
+	Ports []string `json:"ports,omitempty" yaml:"ports,omitempty"`
+	// Errors are the errors of the latest tasks that failed
+	Errors []string `json:"errors,omitempty" yaml:"errors,omitempty"`
+	Status string   `json:"status" yaml:"status"`
+}
+
+// RunStatus writes the stack status, and returns it so that callers can act
+// on the verdict
+func RunStatus(dockerCli *command.DockerCli, opts StatusOptions) (*StackStatus, error) {
+	if opts.format != "" && opts.format != FormatTable && !isStructuredFormat(opts.format) {
+		return nil, fmt.Errorf("Unknown format %q, expected %q, %q or %q", opts.format, FormatTable, FormatJSON, FormatYAML)
+	}
+
+	status, err := GetStatus(context.Background(), dockerCli, opts.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	out := opts.out
+	if out == nil {
+		out = dockerCli.Out()
+	}
+
+	if isStructuredFormat(opts.format) {
+		return status, writeStructured(out, opts.format, status)
+	}
+	return status, status.WriteTable(out)
+}
+
+// GetStatus collects the status of the stack services
+func GetStatus(ctx context.Context, dockerCli *command.DockerCli, namespace string) (*StackStatus, error) {
+	client := dockerCli.Client()
+
+	services, err := getServices(ctx, client, namespace)
+	if err != nil {
+		return nil, err
+	}
+	sortServices(services)
+
+	status := &StackStatus{Namespace: namespace, Services: []ServiceStatus{}}
+	if len(services) == 0 {
+		status.Status = StatusDown
+		return status, nil
+	}
+
+	taskFilter := filters.NewArgs()
+	for _, service := range services {
+		taskFilter.Add("service", service.ID)
+	}
+	tasks, err := client.TaskList(ctx, types.TaskListOptions{Filters: taskFilter})
+	if err != nil {
+		return nil, err
+	}
+
+	tasksByService := map[string][]swarm.Task{}
+	for _, task := range tasks {
+		tasksByService[task.ServiceID] = append(tasksByService[task.ServiceID], task)
+	}
+
+	for _, service := range services {
+		status.Services = append(status.Services, serviceStatus(service, tasksByService[service.ID]))
+	}
+	status.Status = stackVerdict(status.Services)
+
+	return status, nil
+}
+
+func serviceStatus(service swarm.Service, tasks []swarm.Task) ServiceStatus {
+	status := ServiceStatus{
+		Name: service.Spec.Name,
+		ID:   service.ID,
+	}
+
+	if service.Spec.Mode.Global != nil {
+		status.Mode = "global"
+		status.Desired = globalDesired(tasks)
+	} else {
+		status.Mode = "replicated"
+		if service.Spec.Mode.Replicated != nil && service.Spec.Mode.Replicated.Replicas != nil {
+			status.Desired = *service.Spec.Mode.Replicated.Replicas
+		}
+	}
+
+	if service.UpdateStatus != nil {
+		status.UpdateState = string(service.UpdateStatus.State)
+		status.UpdateMessage = service.UpdateStatus.Message
+	}
+
+	for _, port := range service.Endpoint.Ports {
+		if port.PublishedPort != 0 {
+			status.Ports = append(status.Ports, fmt.Sprintf("%d:%d/%s", port.PublishedPort, port.TargetPort, port.Protocol))
+		}
+	}
+
+	// only the latest task in each slot tells if the slot is failing
+	latest := map[string]swarm.Task{}
+	for _, task := range tasks {
+		if task.DesiredState == swarm.TaskStateRunning && task.Status.State == swarm.TaskStateRunning {
+			status.Running++
+		}
+
+		slot := fmt.Sprintf("%d", task.Slot)
+		if service.Spec.Mode.Global != nil {
+			slot = task.NodeID
+		}
+		if current, exists := latest[slot]; !exists || task.Meta.CreatedAt.After(current.Meta.CreatedAt) {
+			latest[slot] = task
+		}
+	}
+	// tasks are named by their slot, or by their node for global services
+	for slot, task := range latest {
+		if task.Status.Err != "" {
+			status.Errors = append(status.Errors, fmt.Sprintf("%s.%s: %s: %s", service.Spec.Name, slot, task.Status.State, task.Status.Err))
+		}
+	}
+	sort.Strings(status.Errors)
+
+	status.Status = serviceVerdict(status)
+	return status
+}
+
+// globalDesired counts the nodes that a global service has tasks meant to
+// run on, as swarm only gives tasks to the nodes that meet its constraints
+func globalDesired(tasks []swarm.Task) uint64 {
+	nodes := map[string]bool{}
+	for _, task := range tasks {
+		if task.DesiredState == swarm.TaskStateRunning && task.NodeID != "" {
+			nodes[task.NodeID] = true
+		}
+	}
+	return uint64(len(nodes))
+}
+
+func serviceVerdict(status ServiceStatus) string {
+	switch {
+	case status.Desired > 0 && status.Running == 0:
+		return StatusDown
+	case status.Running < status.Desired, len(status.Errors) > 0:
+		return StatusDegraded
+	}
+
+	switch swarm.UpdateState(status.UpdateState) {
+	case swarm.UpdateStatePaused, swarm.UpdateStateRollbackStarted, swarm.UpdateStateRollbackCompleted:
+		return StatusDegraded
+	}
+	return StatusHealthy
+}
+
+func stackVerdict(services []ServiceStatus) string {
+	desired, running := uint64(0), uint64(0)
+	verdict := StatusHealthy
+	for _, service := range services {
+		desired += service.Desired
+		running += service.Running
+		if service.Status != StatusHealthy {
+			verdict = StatusDegraded
+		}
+	}
+	if desired > 0 && running == 0 {
+		return StatusDown
+	}
+	return verdict
+}
+
+// WriteTable writes the service status as a table, followed by the verdict
+func (status *StackStatus) WriteTable(out io.Writer) error {
+	writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(writer, statusItemFmt, "NAME", "MODE", "REPLICAS", "UPDATE", "PORTS", "STATUS")
+	for _, service := range status.Services {
+		update := service.UpdateState
+		if update == "" {
+			update = "-"
+		}
+		fmt.Fprintf(writer, statusItemFmt,
+			service.Name,
+			service.Mode,
+			fmt.Sprintf("%d/%d", service.Running, service.Desired),
+			update,
+			strings.Join(service.Ports, ","),
+			service.Status,
+		)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	for _, service := range status.Services {
+		for _, taskErr := range service.Errors {
+			fmt.Fprintf(out, "Error: %s\n", taskErr)
+		}
+	}
+	_, err := fmt.Fprintf(out, "Stack %s is %s\n", status.Namespace, status.Status)
+	return err
+}
@@ -0,0 +1,52 @@
+package stack
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+func TestServiceVerdict(t *testing.T) {
+	for name, test := range map[string]struct {
+		status   ServiceStatus
+		expected string
+	}{
+		"all replicas running":  {ServiceStatus{Desired: 2, Running: 2}, StatusHealthy},
+		"scaled to zero":        {ServiceStatus{Desired: 0, Running: 0}, StatusHealthy},
+		"no replicas running":   {ServiceStatus{Desired: 2, Running: 0}, StatusDown},
+		"some replicas running": {ServiceStatus{Desired: 2, Running: 1}, StatusDegraded},
+		"failing tasks":         {ServiceStatus{Desired: 2, Running: 2, Errors: []string{"app_web.1: failed: exit 1"}}, StatusDegraded},
+		"paused update":         {ServiceStatus{Desired: 2, Running: 2, UpdateState: string(swarm.UpdateStatePaused)}, StatusDegraded},
+		"rolled back update":    {ServiceStatus{Desired: 2, Running: 2, UpdateState: string(swarm.UpdateStateRollbackCompleted)}, StatusDegraded},
+		"completed update":      {ServiceStatus{Desired: 2, Running: 2, UpdateState: string(swarm.UpdateStateCompleted)}, StatusHealthy},
+	} {
+		if verdict := serviceVerdict(test.status); verdict != test.expected {
+			t.Errorf("%s: expected %s, got %s", name, test.expected, verdict)
+		}
+	}
+}
+
+func TestStackVerdict(t *testing.T) {
+	healthy := ServiceStatus{Desired: 1, Running: 1, Status: StatusHealthy}
+	stopped := ServiceStatus{Desired: 0, Running: 0, Status: StatusHealthy}
+	degraded := ServiceStatus{Desired: 2, Running: 1, Status: StatusDegraded}
+	down := ServiceStatus{Desired: 1, Running: 0, Status: StatusDown}
+
+	for name, test := range map[string]struct {
+		services []ServiceStatus
+		expected string
+	}{
+		"no services":             {nil, StatusHealthy},
+		"all services healthy":    {[]ServiceStatus{healthy, healthy}, StatusHealthy},
+		"stopped stack":           {[]ServiceStatus{stopped, stopped}, StatusHealthy},
+		"one service scaled to 0": {[]ServiceStatus{healthy, stopped}, StatusHealthy},
+		"one service degraded":    {[]ServiceStatus{healthy, degraded}, StatusDegraded},
+		"one service down":        {[]ServiceStatus{healthy, down}, StatusDegraded},
+		"nothing desired runs":    {[]ServiceStatus{down, down}, StatusDown},
+		"down next to stopped":    {[]ServiceStatus{stopped, down}, StatusDown},
+	} {
+		if verdict := stackVerdict(test.services); verdict != test.expected {
+			t.Errorf("%s: expected %s, got %s", name, test.expected, verdict)
+		}
+	}
+}
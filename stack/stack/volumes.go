@@ -0,0 +1,184 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/cli/command"
+)
+
+const (
+	volumesItemFmt = "%s\t%s\t%s\t%s\n"
+
+	// How long to wait for stack containers to go before removing volumes
+	volumesRemoveTimeout = 2 * time.Minute
+)
+
+/**
+ * Stack volumes
+ *
+ * Named volumes in a stack are created by the engine on each node that runs
+ * a task which uses them.  They carry the stack namespace label, but this
+ * client can only see and remove the volumes on the node it connects to.
+ */
+
+type VolumesOptions struct {
+	namespace string
+}
+
+func New_VolumesOptions(namespace string) *VolumesOptions {
+	return &VolumesOptions{
+		namespace: namespace,
+	}
+}
+
+// RunVolumes prints the stack volumes on the connected node, with their size
+// and how many containers use them, if the engine provides disk usage.
+func RunVolumes(dockerCli *command.DockerCli, opts VolumesOptions) error {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	volumes, err := getStackVolumes(ctx, dockerCli, opts.namespace)
+	if err != nil {
+		return err
+	}
+
+	out := dockerCli.Out()
+	if len(volumes) == 0 {
+		fmt.Fprintf(out, "Nothing found in stack: %s\n", opts.namespace)
+		return nil
+	}
+
+	// disk usage is not available on older engines, so it is optional
+	usage := map[string]*types.VolumeUsageData{}
+	if diskUsage, err := client.DiskUsage(ctx); err == nil {
+		for _, volume := range diskUsage.Volumes {
+			usage[volume.Name] = volume.UsageData
+		}
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+
+	// Ignore flushing errors
+	defer writer.Flush()
+
+	fmt.Fprintf(writer, volumesItemFmt, "NAME", "DRIVER", "SIZE", "LINKS")
+	for _, volume := range volumes {
+		size, links := "N/A", "N/A"
+		if data, exists := usage[volume.Name]; exists && data != nil {
+			if data.Size >= 0 {
+				size = humanSize(data.Size)
+			}
+			if data.RefCount >= 0 {
+				links = strconv.FormatInt(data.RefCount, 10)
+			}
+		}
+		fmt.Fprintf(writer, volumesItemFmt, volume.Name, volume.Driver, size, links)
+	}
+	return nil
+}
+
+// removeStackVolumes waits for the stack containers on the connected node to
+// be removed, and then removes the stack volumes.  Volumes that could not be
+// removed, and nodes which could not be reached, are reported.
+func removeStackVolumes(ctx context.Context, dockerCli *command.DockerCli, namespace string) bool {
+	client := dockerCli.Client()
+	errOut := dockerCli.Err()
+
+	if err := waitForStackContainers(ctx, dockerCli, namespace, volumesRemoveTimeout); err != nil {
+		fmt.Fprintf(errOut, "Failed to remove volumes: %s\n", err)
+		return true
+	}
+
+	volumes, err := getStackVolumes(ctx, dockerCli, namespace)
+	if err != nil {
+		fmt.Fprintf(errOut, "Failed to list volumes: %s\n", err)
+		return true
+	}
+
+	var leftBehind []string
+	for _, volume := range volumes {
+		fmt.Fprintf(errOut, "Removing volume %s\n", volume.Name)
+		if err := client.VolumeRemove(ctx, volume.Name, false); err != nil {
+			fmt.Fprintf(errOut, "Failed to remove volume %s: %s\n", volume.Name, err)
+			leftBehind = append(leftBehind, volume.Name)
+		}
+	}
+
+	// other swarm nodes may have their own copies of the stack volumes
+	if info, err := client.Info(ctx); err == nil && info.Swarm.Nodes > 1 {
+		fmt.Fprintf(errOut, "Only volumes on node %s were removed, stack volumes may be left behind on the %d other swarm nodes\n", info.Name, info.Swarm.Nodes-1)
+	}
+
+	if len(leftBehind) > 0 {
+		fmt.Fprintf(errOut, "Volumes left behind: %v\n", leftBehind)
+		return true
+	}
+	return false
+}
+
+// waitForStackContainers waits until the connected node has no containers
+// left for the stack, as volumes cannot be removed while they are in use
+func waitForStackContainers(ctx context.Context, dockerCli *command.DockerCli, namespace string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		containers, err := dockerCli.Client().ContainerList(ctx, types.ContainerListOptions{
+			All:     true,
+			Filters: getStackFilter(namespace),
+		})
+		if err != nil {
+			return err
+		}
+		if len(containers) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d stack containers were still present after %s", len(containers), timeout)
+		case <-time.After(convergePollInterval):
+		}
+	}
+}
+
+// getStackVolumes lists the stack volumes on the connected node, by name
+func getStackVolumes(ctx context.Context, dockerCli *command.DockerCli, namespace string) ([]*types.Volume, error) {
+	list, err := dockerCli.Client().VolumeList(ctx, getStackFilter(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, warning := range list.Warnings {
+		fmt.Fprintln(dockerCli.Err(), warning)
+	}
+
+	volumes := list.Volumes
+	sort.Sort(volumesByName(volumes))
+	return volumes, nil
+}
+
+type volumesByName []*types.Volume
+
+func (n volumesByName) Len() int           { return len(n) }
+func (n volumesByName) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
+func (n volumesByName) Less(i, j int) bool { return n[i].Name < n[j].Name }
+
+// humanSize formats a size in bytes using decimal units, as docker does
+func humanSize(size int64) string {
+	units := []string{"B", "kB", "MB", "GB", "TB", "PB"}
+
+	value := float64(size)
+	unit := 0
+	for value >= 1000 && unit < len(units)-1 {
+		value /= 1000
+		unit++
+	}
+	return strconv.FormatFloat(value, 'g', 4, 64) + units[unit]
+}
@@ -0,0 +1,237 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/cli/compose/convert"
+	composetypes "github.com/docker/docker/cli/compose/types"
+	"github.com/docker/docker/pkg/filenotify"
+)
+
+const (
+	// By default wait this long after the last change before redeploying, as
+	// editors often write a file more than once when saving
+	defaultWatchDebounce = 500 * time.Millisecond
+
+	// The fsnotify Chmod bit.  fsnotify is vendored inside docker, so its
+	// Op constants cannot be imported next to the docker filenotify package.
+	watchOpChmod = 0x10
+)
+
+/**
+ * Watch
+ *
+ * Watching a stack deploys it, and then redeploys it whenever the compose
+ * file or one of the env files that it names changes.  A compose file that
+ * does not load is reported and not deployed, and the watch carries on so
+ * that the next save can fix it.
+ */
+
+type WatchOptions struct {
+	deploy   DeployOptions
+	debounce time.Duration
+	cycles   func(WatchCycle)
+}
+
+func New_WatchOptions(deploy DeployOptions) *WatchOptions {
+	return &WatchOptions{
+		deploy:   deploy,
+		debounce: defaultWatchDebounce,
+	}
+}
+
+// Wait this long after the last file change before redeploying
+func (opts *WatchOptions) SetDebounce(debounce time.Duration) {
+	opts.debounce = debounce
+}
+
+// Receive a report of each deploy cycle, instead of writing it to the cli output
+func (opts *WatchOptions) SetCycles(cycles func(WatchCycle)) {
+	opts.cycles = cycles
+}
+
+// WatchCycle reports a single deploy of a watched stack
+type WatchCycle struct {
+	// Number counts the cycles, starting at 1 for the initial deploy
+	Number int
+	// Changed are the watched files that triggered the cycle
+	Changed []string
+	// Plan lists the services that the deploy would create or update
+	Plan ServiceResults
+	// Result is the outcome of the deploy, if it ran
+	Result *DeployResult
+	// Error is set if the compose file did not load, or the deploy failed
+	Error error
+}
+
+// RunWatch deploys the stack, and redeploys it on each change to the watched
+// files until the context is cancelled.  A deploy that is running when the
+// context is cancelled is allowed to finish.
+func RunWatch(ctx context.Context, dockerCli *command.DockerCli, opts WatchOptions) error {
+	if opts.deploy.composefile == "" {
+		return errors.New("Watching a stack needs a Compose file, bundle files are not supported.")
+	}
+
+	watcher, err := filenotify.New()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	report := opts.cycles
+	if report == nil {
+		report = printWatchCycle(dockerCli)
+	}
+
+	files := []string{opts.deploy.composefile}
+	cycle := WatchCycle{Number: 1}
+	for {
+		if envFiles, loaded := runWatchCycle(ctx, dockerCli, opts.deploy, &cycle); loaded {
+			files = append([]string{opts.deploy.composefile}, envFiles...)
+		}
+		report(cycle)
+
+		// editors may replace files when saving, which drops their watch
+		for _, file := range files {
+			watcher.Remove(file)
+			if err := watcher.Add(file); err != nil {
+				fmt.Fprintf(dockerCli.Err(), "Could not watch %s: %s\n", file, err)
+			}
+		}
+
+		changed, err := waitForChanges(ctx, watcher, opts.debounce)
+		if ctx.Err() != nil {
+			fmt.Fprintf(dockerCli.Out(), "Stopped watching stack %s\n", opts.deploy.namespace)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		cycle = WatchCycle{Number: cycle.Number + 1, Changed: changed}
+	}
+}
+
+// runWatchCycle validates the compose file, and deploys it if it loads,
+// returning the env files that it names
+func runWatchCycle(ctx context.Context, dockerCli *command.DockerCli, opts DeployOptions, cycle *WatchCycle) ([]string, bool) {
+	config, err := loadStackConfig(opts.composefile, opts.namespace)
+	if err != nil {
+		cycle.Error = fmt.Errorf("Not deploying, the Compose file is invalid: %s", err)
+		return nil, false
+	}
+
+	cycle.Plan = watchPlan(ctx, dockerCli, opts, config)
+	cycle.Result, cycle.Error = RunDeploy(dockerCli, opts)
+
+	workingDir, err := composeWorkingDir(opts.composefile)
+	if err != nil {
+		return nil, true
+	}
+	return composeEnvFiles(config, workingDir), true
+}
+
+// watchPlan lists the services of the compose file, and whether a swarm
+// deploy will create or update them
+func watchPlan(ctx context.Context, dockerCli *command.DockerCli, opts DeployOptions, config *composetypes.Config) ServiceResults {
+	namespace := convert.NewNamespace(opts.namespace)
+
+	existing := map[string]string{}
+	if opts.mode == DeployModeSwarm {
+		if services, err := getServices(ctx, dockerCli.Client(), opts.namespace); err == nil {
+			for _, service := range services {
+				existing[service.Spec.Name] = service.ID
+			}
+		}
+	}
+
+	plan := ServiceResults{}
+	for _, service := range config.Services {
+		result := ServiceResult{Name: namespace.Scope(service.Name), Action: "create"}
+		if opts.mode == DeployModeLocal {
+			result.Action = "deploy"
+		} else if id, exists := existing[result.Name]; exists {
+			result.ID = id
+			result.Action = "update"
+		}
+		plan = append(plan, result)
+	}
+	sort.Sort(plan)
+	return plan
+}
+
+// composeEnvFiles lists the env files of the compose services, which the
+// loader reads relative to the compose working directory
+func composeEnvFiles(config *composetypes.Config, workingDir string) []string {
+	seen := map[string]bool{}
+	files := []string{}
+	for _, service := range config.Services {
+		for _, file := range service.EnvFile {
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(workingDir, file)
+			}
+			if !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// waitForChanges blocks until a watched file changes, and then until no
+// further changes arrive for the debounce period
+func waitForChanges(ctx context.Context, watcher filenotify.FileWatcher, debounce time.Duration) ([]string, error) {
+	changed := map[string]bool{}
+	var settled <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-watcher.Errors():
+			return nil, err
+		case event := <-watcher.Events():
+			if event.Op&^watchOpChmod == 0 {
+				// only the file mode changed
+				continue
+			}
+			changed[event.Name] = true
+			settled = time.After(debounce)
+		case <-settled:
+			files := []string{}
+			for file := range changed {
+				files = append(files, file)
+			}
+			sort.Strings(files)
+			return files, nil
+		}
+	}
+}
+
+// printWatchCycle writes the plan and outcome of each cycle to the cli output
+func printWatchCycle(dockerCli *command.DockerCli) func(WatchCycle) {
+	return func(cycle WatchCycle) {
+		if len(cycle.Changed) > 0 {
+			fmt.Fprintf(dockerCli.Out(), "Changed: %s\n", strings.Join(cycle.Changed, ", "))
+		}
+		for _, planned := range cycle.Plan {
+			fmt.Fprintf(dockerCli.Out(), "Plan %d: %s %s\n", cycle.Number, planned.Action, planned.Name)
+		}
+
+		switch {
+		case cycle.Error != nil:
+			fmt.Fprintf(dockerCli.Err(), "Deploy %d failed: %s\n", cycle.Number, cycle.Error)
+		case cycle.Result != nil:
+			fmt.Fprintf(dockerCli.Out(), "Deploy %d finished: %d services at %s\n", cycle.Number, len(cycle.Result.Services), time.Now().Format("15:04:05"))
+		}
+		fmt.Fprintln(dockerCli.Out(), "Watching for changes")
+	}
+}
@@ -0,0 +1,106 @@
+package stack
+
+import (
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_STATUS = "dockercli.stack.monitor.status"
+)
+
+/**
+ * Status operation
+ */
+
+// Operation that summarizes the state of the stack services
+type DockercliStackStatusOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (status *DockercliStackStatusOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_STATUS
+}
+
+// Label the operation
+func (status *DockercliStackStatusOperation) Label() string {
+	return "Status"
+}
+
+// Description for the operation
+func (status *DockercliStackStatusOperation) Description() string {
+	return "Summarize replicas, updates, task errors and ports of the stack services.  Fails unless the stack is healthy."
+}
+
+// Man page for the operation
+func (status *DockercliStackStatusOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (status *DockercliStackStatusOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (status *DockercliStackStatusOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use a status Opts property, with a default set to the configured StatusOptions
+	props.Add(api_property.Property(status.StatusOptionsProperty()))
+	// The status is passed back in this property
+	props.Add(api_property.Property(&DockercliStackStatusProperty{}))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (status *DockercliStackStatusOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (status *DockercliStackStatusOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_STATUSOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.StatusOptions)
+
+		cli := status.DockerCli()
+
+		log.WithFields(log.Fields{"StatusOptions": opts}).Info("Running Status using docker cli stack")
+
+		stackStatus, err := handler_dockercli_stack_imported.RunStatus(cli, opts)
+
+		if stackStatus != nil {
+			if statusProp, found := props.Get(OPERATION_PROPERTY_DOCKER_STACK_STATUS_KEY); found {
+				statusProp.Set(stackStatus)
+			}
+			if err == nil && stackStatus.Status != handler_dockercli_stack_imported.StatusHealthy {
+				err = fmt.Errorf("Stack %s is %s", stackStatus.Namespace, stackStatus.Status)
+			}
+		}
+
+		if err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}
@@ -0,0 +1,91 @@
+package stack
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	api_operation "github.com/wunderkraut/radi-api/operation"
+	api_property "github.com/wunderkraut/radi-api/property"
+	api_result "github.com/wunderkraut/radi-api/result"
+	api_usage "github.com/wunderkraut/radi-api/usage"
+
+	handler_dockercli "github.com/wunderkraut/radi-handler-dockercli"
+	handler_dockercli_stack_imported "github.com/wunderkraut/radi-handler-dockercli/stack/stack" // "github.com/docker/docker/cli/command/stack"
+)
+
+const (
+	OPERATION_ID_DOCKERCLI_STACK_VOLUMES = "dockercli.stack.volumes"
+)
+
+/**
+ * Volumes operation
+ */
+
+// Operation that lists the stack volumes on the connected node
+type DockercliStackVolumesOperation struct {
+	handler_dockercli.DockercliOperationBase
+	DockercliStackOperationBase
+}
+
+// Id the operation
+func (volumes *DockercliStackVolumesOperation) Id() string {
+	return OPERATION_ID_DOCKERCLI_STACK_VOLUMES
+}
+
+// Label the operation
+func (volumes *DockercliStackVolumesOperation) Label() string {
+	return "Volumes"
+}
+
+// Description for the operation
+func (volumes *DockercliStackVolumesOperation) Description() string {
+	return "List the stack volumes on the connected node, with their size and usage where available."
+}
+
+// Man page for the operation
+func (volumes *DockercliStackVolumesOperation) Help() string {
+	return ""
+}
+
+// Define the operations as externally used
+func (volumes *DockercliStackVolumesOperation) Usage() api_usage.Usage {
+	return api_operation.Usage_External()
+}
+
+// Return Operation properties
+func (volumes *DockercliStackVolumesOperation) Properties() api_property.Properties {
+	props := api_property.New_SimplePropertiesEmpty()
+
+	// Use a volumes Opts property, with a default set to the configured VolumesOptions
+	props.Add(api_property.Property(volumes.VolumesOptionsProperty()))
+
+	return props.Properties()
+}
+
+// Validate the operation
+func (volumes *DockercliStackVolumesOperation) Validate() api_result.Result {
+	return api_result.MakeSuccessfulResult()
+}
+
+// Execute the operation
+func (volumes *DockercliStackVolumesOperation) Exec(props api_property.Properties) api_result.Result {
+	res := api_result.New_StandardResult()
+
+	go func() {
+		optsProp, _ := props.Get(OPERATION_PROPERTY_DOCKER_STACK_VOLUMESOPTIONS_KEY)
+		opts := optsProp.Get().(handler_dockercli_stack_imported.VolumesOptions)
+
+		cli := volumes.DockerCli()
+
+		log.WithFields(log.Fields{"VolumesOptions": opts}).Info("Running Volumes using docker cli stack")
+
+		if err := handler_dockercli_stack_imported.RunVolumes(cli, opts); err == nil {
+			res.MarkSuccess()
+		} else {
+			res.AddError(err)
+			res.MarkFailed()
+		}
+		res.MarkFinished()
+	}()
+
+	return res.Result()
+}